@@ -0,0 +1,63 @@
+package mobileid
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	AuditSessionCreated      = "session_created"
+	AuditSessionCreateFailed = "session_create_failed"
+	AuditSessionCompleted    = "session_completed"
+	AuditSessionPollFailed   = "session_poll_failed"
+	AuditJobCompleted        = "job_completed"
+	AuditJobFailed           = "job_failed"
+)
+
+// AuditRecord represents the authentication event written to the audit sink
+type AuditRecord struct {
+	Time                   time.Time `json:"time"`
+	Event                  string    `json:"event"`
+	SessionId              string    `json:"sessionId,omitempty"`
+	PhoneNumber            string    `json:"phoneNumber,omitempty"`
+	NationalIdentityNumber string    `json:"nationalIdentityNumber,omitempty"`
+	Result                 string    `json:"result,omitempty"`
+	Error                  string    `json:"error,omitempty"`
+}
+
+// AuditSink is an interface records the authentication events of the client and the worker,
+// the failure of the sink is logged and does not fail the authentication
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// noopAuditSink is the default audit sink of the client and the worker
+type noopAuditSink struct{}
+
+func (noopAuditSink) Record(context.Context, AuditRecord) error { return nil }
+
+// audit writes the record to the audit sink, the record time is set when it is empty.
+// The record is written even when the context is cancelled, e.g. when the session is timed out
+func audit(ctx context.Context, sink AuditSink, logger *slog.Logger, record AuditRecord) {
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	if err := sink.Record(ctx, record); err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "Mobile-ID audit record failed",
+			slog.String("event", record.Event),
+			slog.String("session_id", record.SessionId),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// errorMessage returns the message of the error or empty string when there is no error
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
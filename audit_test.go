@@ -0,0 +1,202 @@
+package mobileid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+	err     error
+}
+
+func (s *testAuditSink) Record(_ context.Context, record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = append(s.records, record)
+	return s.err
+}
+
+func (s *testAuditSink) events() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]string, 0, len(s.records))
+	for _, record := range s.records {
+		events = append(events, record.Event)
+	}
+	return events
+}
+
+func Test_Audit_Authenticate(t *testing.T) {
+	sink := &testAuditSink{}
+
+	testServer, store := newTestAuthenticationServer(t, 1)
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(store).
+		WithAuditSink(sink)
+
+	_, err := c.Authenticate(context.Background(), "+37269930366", "51307149560", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{AuditSessionCreated, AuditSessionCompleted}, sink.events())
+
+	created := sink.records[0]
+	assert.Equal(t, "eb03076a-9f97-423e-af2e-b14c0a481ff9", created.SessionId)
+	assert.Equal(t, "+37269930366", created.PhoneNumber)
+	assert.Equal(t, "51307149560", created.NationalIdentityNumber)
+	assert.False(t, created.Time.IsZero())
+
+	completed := sink.records[1]
+	assert.Equal(t, "eb03076a-9f97-423e-af2e-b14c0a481ff9", completed.SessionId)
+	assert.Equal(t, "51307149560", completed.NationalIdentityNumber)
+	assert.Equal(t, OK, completed.Result)
+	assert.Empty(t, completed.Error)
+}
+
+func Test_Audit_Failure(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected AuditRecord
+	}{
+		{
+			name: "USER_CANCELLED",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+					return
+				}
+				w.Write([]byte(`{"state": "COMPLETE", "result": "USER_CANCELLED"}`))
+			},
+			expected: AuditRecord{
+				Event:                  AuditSessionCompleted,
+				SessionId:              "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+				PhoneNumber:            "+37269930366",
+				NationalIdentityNumber: "51307149560",
+				Result:                 USER_CANCELLED,
+				Error:                  (&Error{Code: USER_CANCELLED}).Error(),
+			},
+		},
+		{
+			name: "Session not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expected: AuditRecord{
+				Event:     AuditSessionPollFailed,
+				SessionId: "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+			},
+		},
+		{
+			name: "Creation failed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			expected: AuditRecord{
+				Event:                  AuditSessionCreateFailed,
+				PhoneNumber:            "+37269930366",
+				NationalIdentityNumber: "51307149560",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &testAuditSink{}
+
+			testServer := httptest.NewServer(tt.handler)
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithAuditSink(sink)
+
+			_, err := c.Authenticate(context.Background(), "+37269930366", "51307149560", nil)
+			assert.Error(t, err)
+
+			record := sink.records[len(sink.records)-1]
+			assert.Equal(t, tt.expected.Event, record.Event)
+			assert.Equal(t, tt.expected.SessionId, record.SessionId)
+			assert.Equal(t, tt.expected.PhoneNumber, record.PhoneNumber)
+			assert.Equal(t, tt.expected.NationalIdentityNumber, record.NationalIdentityNumber)
+			assert.Equal(t, tt.expected.Result, record.Result)
+			assert.NotEmpty(t, record.Error)
+			if tt.expected.Error != "" {
+				assert.Equal(t, tt.expected.Error, record.Error)
+			}
+		})
+	}
+}
+
+func Test_Audit_Worker(t *testing.T) {
+	sink := &testAuditSink{}
+
+	testServer, store := newTestAuthenticationServer(t, 1)
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(store)
+
+	w := NewWorker(c).WithConcurrency(1).WithAuditSink(sink)
+	w.Start(context.Background())
+	defer w.Stop()
+
+	session, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+	assert.NoError(t, err)
+
+	result := <-w.Process(context.Background(), session.Id)
+	assert.NoError(t, result.Err)
+
+	assert.Equal(t, []string{AuditJobCompleted}, sink.events())
+	assert.Equal(t, session.Id, sink.records[0].SessionId)
+	assert.Equal(t, "51307149560", sink.records[0].NationalIdentityNumber)
+}
+
+func Test_Audit_SinkError(t *testing.T) {
+	sink := &testAuditSink{err: errors.New("disk is full")}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	testServer, store := newTestAuthenticationServer(t, 0)
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(store).
+		WithLogger(logger).
+		WithAuditSink(sink)
+
+	_, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `"msg":"Mobile-ID audit record failed"`)
+	assert.Contains(t, buf.String(), `"error":"disk is full"`)
+}
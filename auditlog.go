@@ -0,0 +1,292 @@
+package mobileid
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+// MaxAuditRecordSize is the maximum accepted size of the audit log line
+const MaxAuditRecordSize = 1024 * 1024
+
+// AuditEntry represents the line of the audit log, the hash covers the entry with the hash of the previous entry,
+// so a removed or modified entry breaks the chain
+type AuditEntry struct {
+	Seq uint64 `json:"seq"`
+	AuditRecord
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// AuditHead represents the sequence number and the hash of the last entry of the audit log,
+// the head kept outside the log detects the entries removed from its end
+type AuditHead struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// AuditLogError represents the audit log verification error with the line of the invalid entry, it matches
+// ErrAuditLogGap, ErrAuditLogTampered, ErrAuditLogInvalid, ErrAuditLogTruncated or ErrAuditLogTornTail with errors.Is
+type AuditLogError struct {
+	Line int
+	Err  error
+}
+
+// Error returns the error message
+func (e *AuditLogError) Error() string {
+	return fmt.Sprintf("%s (line: %d)", e.Err, e.Line)
+}
+
+// Unwrap returns the verification error
+func (e *AuditLogError) Unwrap() error {
+	return e.Err
+}
+
+// AuditLog is the append-only JSONL file implementation of the AuditSink interface
+type AuditLog struct {
+	mu       sync.Mutex
+	file     auditFile
+	key      []byte
+	seq      uint64
+	prevHash string
+	size     int64
+}
+
+// auditFile is the file of the audit log, it is replaced in the tests to fail the writes
+type auditFile interface {
+	io.Writer
+	Sync() error
+	Truncate(size int64) error
+	Close() error
+}
+
+// NewAuditLog opens the audit log file for appending, the file is created when it does not exist.
+// The entries are hashed with HMAC-SHA256 of the key, so the chain can not be recomputed without the key,
+// ErrAuditLogKey is returned for the empty key.
+// The existing entries are verified and the chain is continued from the last entry, the last line without
+// the line break is the entry partly written by a crash, which was never acknowledged by Record, it is removed
+func NewAuditLog(path string, key []byte) (*AuditLog, error) {
+	if len(key) == 0 {
+		return nil, errors.ErrAuditLogKey
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	head, size, err := verifyAuditLog(file, key, nil)
+	if logErr, ok := err.(*AuditLogError); ok && logErr.Err == errors.ErrAuditLogTornTail {
+		err = truncateAuditLog(file, size)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &AuditLog{
+		file:     file,
+		key:      append([]byte(nil), key...),
+		seq:      head.Seq,
+		prevHash: head.Hash,
+		size:     size,
+	}, nil
+}
+
+// Record appends the record to the audit log and syncs the file, the file is truncated back to the last entry
+// when the write or the sync fails, so the failed record does not break the chain
+func (l *AuditLog) Record(_ context.Context, record AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := AuditEntry{
+		Seq:         l.seq + 1,
+		AuditRecord: record,
+		PrevHash:    l.prevHash,
+	}
+
+	hash, err := hashAuditEntry(l.key, entry)
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	line = append(line, '\n')
+
+	if _, err = l.file.Write(line); err != nil {
+		return l.rollback(err)
+	}
+	if err = l.file.Sync(); err != nil {
+		return l.rollback(err)
+	}
+
+	l.seq = entry.Seq
+	l.prevHash = entry.Hash
+	l.size += int64(len(line))
+
+	return nil
+}
+
+// rollback removes the partly written or not synced record from the end of the audit log
+func (l *AuditLog) rollback(err error) error {
+	if truncateErr := l.file.Truncate(l.size); truncateErr != nil {
+		return stderrors.Join(err, truncateErr)
+	}
+
+	return err
+}
+
+// Head returns the head of the audit log, keep it outside the log to detect the truncation with VerifyAuditLog
+func (l *AuditLog) Head() AuditHead {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return AuditHead{Seq: l.seq, Hash: l.prevHash}
+}
+
+// Close closes the audit log file
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// VerifyAuditLog verifies the hash chain of the audit log written with the key and returns its head,
+// the AuditLogError is returned for the first entry that is missing, modified or not a valid JSON.
+// ErrAuditLogTornTail is returned for the last line without the line break, the head of the preceding entries
+// is returned with it, NewAuditLog removes such line. When the expected head is given, the log must contain it,
+// otherwise ErrAuditLogTruncated is returned
+func VerifyAuditLog(r io.Reader, key []byte, expected *AuditHead) (AuditHead, error) {
+	if len(key) == 0 {
+		return AuditHead{}, errors.ErrAuditLogKey
+	}
+
+	head, _, err := verifyAuditLog(r, key, expected)
+	return head, err
+}
+
+// verifyAuditLog verifies the hash chain of the audit log and returns its head
+// and the size of the verified entries
+func verifyAuditLog(r io.Reader, key []byte, expected *AuditHead) (AuditHead, int64, error) {
+	var head AuditHead
+	var size int64
+
+	reader := bufio.NewReaderSize(r, 64*1024)
+
+	line := 1
+	for ; ; line++ {
+		data, err := readAuditLine(reader)
+		if err == io.EOF {
+			if len(data) > 0 {
+				return head, size, &AuditLogError{Line: line, Err: errors.ErrAuditLogTornTail}
+			}
+			break
+		}
+		if err != nil {
+			return head, size, err
+		}
+
+		var entry AuditEntry
+		if err = json.Unmarshal(data, &entry); err != nil {
+			return head, size, &AuditLogError{Line: line, Err: errors.ErrAuditLogInvalid}
+		}
+
+		// the hash covers the entry as written by Record, so any other bytes of the line,
+		// e.g. the unknown fields or the whitespace, are the modification of the entry
+		canonical, err := json.Marshal(entry)
+		if err != nil {
+			return head, size, err
+		}
+		if !bytes.Equal(canonical, data) {
+			return head, size, &AuditLogError{Line: line, Err: errors.ErrAuditLogTampered}
+		}
+
+		if entry.Seq != head.Seq+1 || entry.PrevHash != head.Hash {
+			return head, size, &AuditLogError{Line: line, Err: errors.ErrAuditLogGap}
+		}
+
+		hash, err := hashAuditEntry(key, entry)
+		if err != nil {
+			return head, size, err
+		}
+		if !hmac.Equal([]byte(hash), []byte(entry.Hash)) {
+			return head, size, &AuditLogError{Line: line, Err: errors.ErrAuditLogTampered}
+		}
+
+		if expected != nil && entry.Seq == expected.Seq && entry.Hash != expected.Hash {
+			return head, size, &AuditLogError{Line: line, Err: errors.ErrAuditLogTampered}
+		}
+
+		head = AuditHead{Seq: entry.Seq, Hash: entry.Hash}
+		size += int64(len(data)) + 1
+	}
+
+	if expected != nil && head.Seq < expected.Seq {
+		return head, size, &AuditLogError{Line: line, Err: errors.ErrAuditLogTruncated}
+	}
+
+	return head, size, nil
+}
+
+// readAuditLine reads the line without the line break, io.EOF is returned with the last line without the line break,
+// bufio.ErrTooLong is returned for the line longer than MaxAuditRecordSize
+func readAuditLine(reader *bufio.Reader) ([]byte, error) {
+	var line []byte
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(line)+len(chunk) > MaxAuditRecordSize+1 {
+			return nil, bufio.ErrTooLong
+		}
+		line = append(line, chunk...)
+
+		switch err {
+		case nil:
+			return line[:len(line)-1], nil
+		case bufio.ErrBufferFull:
+			continue
+		default:
+			return line, err
+		}
+	}
+}
+
+// truncateAuditLog removes the partly written entry from the end of the audit log
+func truncateAuditLog(file *os.File, size int64) error {
+	if err := file.Truncate(size); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// hashAuditEntry returns the HMAC-SHA256 of the entry without its own hash
+func hashAuditEntry(key []byte, entry AuditEntry) (string, error) {
+	entry.Hash = ""
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,399 @@
+package mobileid
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testAuditLogKey = []byte("audit log key")
+
+func newTestAuditLog(t *testing.T, records int) string {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	log, err := NewAuditLog(path, testAuditLogKey)
+	assert.NoError(t, err)
+
+	for i := 0; i < records; i++ {
+		err = log.Record(context.Background(), AuditRecord{
+			Time:      time.Date(2025, 2, 23, 17, 31, i, 0, time.UTC),
+			Event:     AuditSessionCreated,
+			SessionId: "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+		})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, log.Close())
+
+	return path
+}
+
+func readAuditLog(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func Test_AuditLog(t *testing.T) {
+	path := newTestAuditLog(t, 2)
+
+	log, err := NewAuditLog(path, testAuditLogKey)
+	assert.NoError(t, err)
+	assert.NoError(t, log.Record(context.Background(), AuditRecord{Event: AuditSessionCompleted, Result: OK}))
+	head := log.Head()
+	assert.Equal(t, uint64(3), head.Seq)
+	assert.NoError(t, log.Close())
+
+	lines := readAuditLog(t, path)
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[2], `"seq":3`)
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	verified, err := VerifyAuditLog(file, testAuditLogKey, &head)
+	assert.NoError(t, err)
+	assert.Equal(t, head, verified)
+}
+
+func Test_VerifyAuditLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   func(lines []string) []string
+		torn     string
+		key      []byte
+		expected func(lines []string) *AuditHead
+		line     int
+		err      error
+	}{
+		{
+			name:   "Success",
+			before: func(lines []string) []string { return lines },
+		},
+		{
+			name:   "Empty",
+			before: func(lines []string) []string { return nil },
+		},
+		{
+			name: "Removed entry",
+			before: func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			line: 2,
+			err:  ErrAuditLogGap,
+		},
+		{
+			name: "Removed first entry",
+			before: func(lines []string) []string {
+				return lines[1:]
+			},
+			line: 1,
+			err:  ErrAuditLogGap,
+		},
+		{
+			name: "Modified entry",
+			before: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], AuditSessionCreated, AuditSessionCompleted, 1)
+				return lines
+			},
+			line: 2,
+			err:  ErrAuditLogTampered,
+		},
+		{
+			name: "Reordered entries",
+			before: func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			line: 2,
+			err:  ErrAuditLogGap,
+		},
+		{
+			name: "Invalid JSON",
+			before: func(lines []string) []string {
+				lines[2] = "{"
+				return lines
+			},
+			line: 3,
+			err:  ErrAuditLogInvalid,
+		},
+		{
+			name: "Success: Expected head",
+			before: func(lines []string) []string {
+				return lines
+			},
+			expected: func(lines []string) *AuditHead { return auditHead(t, lines[2]) },
+		},
+		{
+			name: "Success: Entries after the expected head",
+			before: func(lines []string) []string {
+				return lines
+			},
+			expected: func(lines []string) *AuditHead { return auditHead(t, lines[1]) },
+		},
+		{
+			name: "Removed last entry",
+			before: func(lines []string) []string {
+				return lines[:2]
+			},
+			expected: func(lines []string) *AuditHead { return &AuditHead{Seq: 3, Hash: "unknown"} },
+			line:     3,
+			err:      ErrAuditLogTruncated,
+		},
+		{
+			name: "Expected head does not match",
+			before: func(lines []string) []string {
+				return lines
+			},
+			expected: func(lines []string) *AuditHead { return &AuditHead{Seq: 2, Hash: "unknown"} },
+			line:     2,
+			err:      ErrAuditLogTampered,
+		},
+		{
+			name: "Recomputed without the key",
+			before: func(lines []string) []string {
+				var prevHash string
+				for i, line := range lines {
+					var entry AuditEntry
+					assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+					entry.PrevHash = prevHash
+					entry.SessionId = "00000000-0000-0000-0000-000000000000"
+					entry.Hash = ""
+
+					data, err := json.Marshal(entry)
+					assert.NoError(t, err)
+					sum := sha256.Sum256(data)
+					entry.Hash = hex.EncodeToString(sum[:])
+					prevHash = entry.Hash
+
+					data, err = json.Marshal(entry)
+					assert.NoError(t, err)
+					lines[i] = string(data)
+				}
+				return lines
+			},
+			line: 1,
+			err:  ErrAuditLogTampered,
+		},
+		{
+			name: "Unknown field",
+			before: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"seq":2,`, `"seq":2,"extra":"forged",`, 1)
+				return lines
+			},
+			line: 2,
+			err:  ErrAuditLogTampered,
+		},
+		{
+			name: "Case-variant duplicate field",
+			before: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"seq":2,`, `"seq":2,"Event":"session_completed",`, 1)
+				return lines
+			},
+			line: 2,
+			err:  ErrAuditLogTampered,
+		},
+		{
+			name: "Whitespace",
+			before: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"seq":2,`, `"seq": 2,`, 1)
+				return lines
+			},
+			line: 2,
+			err:  ErrAuditLogTampered,
+		},
+		{
+			name: "Torn last entry",
+			before: func(lines []string) []string {
+				return lines
+			},
+			torn: `{"seq":4,"time":"2025-02`,
+			line: 4,
+			err:  ErrAuditLogTornTail,
+		},
+		{
+			name: "Another key",
+			before: func(lines []string) []string {
+				return lines
+			},
+			key:  []byte("another key"),
+			line: 1,
+			err:  ErrAuditLogTampered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := tt.before(readAuditLog(t, newTestAuditLog(t, 3)))
+
+			var buf bytes.Buffer
+			for _, line := range lines {
+				buf.WriteString(line + "\n")
+			}
+			buf.WriteString(tt.torn)
+
+			key := testAuditLogKey
+			if tt.key != nil {
+				key = tt.key
+			}
+
+			var expected *AuditHead
+			if tt.expected != nil {
+				expected = tt.expected(lines)
+			}
+
+			_, err := VerifyAuditLog(&buf, key, expected)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+
+				var logErr *AuditLogError
+				assert.ErrorAs(t, err, &logErr)
+				assert.Equal(t, tt.line, logErr.Line)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_NewAuditLog_Tampered(t *testing.T) {
+	path := newTestAuditLog(t, 2)
+
+	lines := readAuditLog(t, path)
+	lines[0] = strings.Replace(lines[0], "eb03076a", "00000000", 1)
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+
+	log, err := NewAuditLog(path, testAuditLogKey)
+	assert.ErrorIs(t, err, ErrAuditLogTampered)
+	assert.Nil(t, log)
+}
+
+func Test_NewAuditLog_TornTail(t *testing.T) {
+	path := newTestAuditLog(t, 2)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"seq":3,"time":"2025-02`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	log, err := NewAuditLog(path, testAuditLogKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), log.Head().Seq)
+	assert.NoError(t, log.Record(context.Background(), AuditRecord{Event: AuditSessionCompleted, Result: OK}))
+	head := log.Head()
+	assert.NoError(t, log.Close())
+
+	lines := readAuditLog(t, path)
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[2], `"seq":3`)
+
+	file, err = os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	_, err = VerifyAuditLog(file, testAuditLogKey, &head)
+	assert.NoError(t, err)
+}
+
+// failingAuditFile writes the half of the line and fails the write or fails the sync
+type failingAuditFile struct {
+	*os.File
+	writeErr error
+	syncErr  error
+}
+
+func (f *failingAuditFile) Write(p []byte) (int, error) {
+	if f.writeErr != nil {
+		n, _ := f.File.Write(p[:len(p)/2])
+		return n, f.writeErr
+	}
+
+	return f.File.Write(p)
+}
+
+func (f *failingAuditFile) Sync() error {
+	if f.syncErr != nil {
+		return f.syncErr
+	}
+
+	return f.File.Sync()
+}
+
+func Test_AuditLog_Record_Failure(t *testing.T) {
+	tests := []struct {
+		name string
+		file func(file *os.File) auditFile
+	}{
+		{
+			name: "Partial write",
+			file: func(file *os.File) auditFile {
+				return &failingAuditFile{File: file, writeErr: errors.New("disk is full")}
+			},
+		},
+		{
+			name: "Sync failure",
+			file: func(file *os.File) auditFile {
+				return &failingAuditFile{File: file, syncErr: errors.New("input/output error")}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := newTestAuditLog(t, 2)
+
+			log, err := NewAuditLog(path, testAuditLogKey)
+			assert.NoError(t, err)
+
+			file := log.file.(*os.File)
+			log.file = tt.file(file)
+			assert.Error(t, log.Record(context.Background(), AuditRecord{Event: AuditSessionCompleted, Result: OK}))
+			assert.Equal(t, uint64(2), log.Head().Seq)
+
+			log.file = file
+			assert.NoError(t, log.Record(context.Background(), AuditRecord{Event: AuditSessionCompleted, Result: OK}))
+			head := log.Head()
+			assert.NoError(t, log.Close())
+
+			lines := readAuditLog(t, path)
+			assert.Len(t, lines, 3)
+
+			log, err = NewAuditLog(path, testAuditLogKey)
+			assert.NoError(t, err)
+			assert.Equal(t, head, log.Head())
+			assert.NoError(t, log.Close())
+		})
+	}
+}
+
+func Test_NewAuditLog_EmptyKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	log, err := NewAuditLog(path, nil)
+	assert.Equal(t, ErrAuditLogKey, err)
+	assert.Nil(t, log)
+
+	_, err = VerifyAuditLog(strings.NewReader(""), nil, nil)
+	assert.Equal(t, ErrAuditLogKey, err)
+}
+
+// auditHead returns the head of the audit log line
+func auditHead(t *testing.T, line string) *AuditHead {
+	t.Helper()
+
+	var entry AuditEntry
+	assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+	return &AuditHead{Seq: entry.Seq, Hash: entry.Hash}
+}
@@ -2,9 +2,13 @@ package mobileid
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/tab/mobileid/internal/errors"
+	"github.com/tab/mobileid/internal/models"
 	"github.com/tab/mobileid/internal/requests"
 	"github.com/tab/mobileid/internal/utils"
 )
@@ -33,40 +37,161 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("authentication failed: %s", e.Code)
 }
 
-// CreateSession creates authentication session with the Mobile-ID provider
-func (c *client) CreateSession(ctx context.Context, phoneNumber, nationalIdentityNumber string) (*Session, error) {
-	session, err := requests.CreateAuthenticationSession(ctx, c.config, phoneNumber, nationalIdentityNumber)
+// Is reports whether the target is the error of the result code
+func (e *Error) Is(target error) bool {
+	err, ok := resultErrors[e.Code]
+	return ok && err == target
+}
+
+// CreateSession creates authentication session with the Mobile-ID provider,
+// the options override the text, the text format, the language and the hash type of the client for the session
+func (c *client) CreateSession(
+	ctx context.Context,
+	phoneNumber, nationalIdentityNumber string,
+	opts ...SessionOption,
+) (result *Session, err error) {
+	ctx, span := c.startSpan(ctx, "mobileid.CreateSession")
+	defer func() { endSpan(span, err) }()
+
+	cfg, err := c.sessionConfig(opts)
 	if err != nil {
 		return nil, err
 	}
+	span.SetAttributes(attributeHashType.String(cfg.HashType))
+
+	start := time.Now()
+	session, err := requests.CreateAuthenticationSession(ctx, c.http(), cfg, phoneNumber, nationalIdentityNumber)
+	c.metrics.CreateLatency(FlowAuthentication, time.Since(start))
+	if err != nil {
+		c.recordProviderError(FlowAuthentication, err)
+		audit(ctx, c.auditSink, c.logger, AuditRecord{
+			Event:                  AuditSessionCreateFailed,
+			PhoneNumber:            phoneNumber,
+			NationalIdentityNumber: nationalIdentityNumber,
+			Error:                  err.Error(),
+		})
+		c.logError(ctx, "Mobile-ID authentication session creation failed", err,
+			c.pii("phone_number", phoneNumber),
+			c.pii("national_identity_number", nationalIdentityNumber),
+		)
+		return nil, err
+	}
+
+	span.SetAttributes(attributeSessionId.String(session.Id))
+
+	createdAt := time.Now()
+	result = &Session{
+		Id:                     session.Id,
+		Code:                   session.Code,
+		Hash:                   session.Hash,
+		HashType:               cfg.HashType,
+		PhoneNumber:            phoneNumber,
+		NationalIdentityNumber: nationalIdentityNumber,
+		URL:                    session.URL,
+		CreatedAt:              createdAt,
+		ExpiresAt:              createdAt.Add(SessionLifetime),
+	}
+	c.sessions.store(result)
+	c.metrics.SessionCreated(FlowAuthentication)
+	audit(ctx, c.auditSink, c.logger, AuditRecord{
+		Time:                   createdAt.UTC(),
+		Event:                  AuditSessionCreated,
+		SessionId:              result.Id,
+		PhoneNumber:            phoneNumber,
+		NationalIdentityNumber: nationalIdentityNumber,
+	})
+
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "Mobile-ID authentication session created",
+		slog.String("session_id", result.Id),
+		slog.String("url", result.URL),
+		c.pii("phone_number", phoneNumber),
+		c.pii("national_identity_number", nationalIdentityNumber),
+	)
 
-	return (*Session)(session), nil
+	return result, nil
 }
 
-// FetchSession fetches the authentication session from the Mobile-ID provider
+// FetchSession fetches the authentication session created by this client from the Mobile-ID provider
+// and verifies the signature against the hash of the session, the session is loaded from the in-memory
+// session store of the client, so it must be created by the same client instance in this process,
+// ErrMissingSessionHash is returned otherwise, use FetchSessionFor with the persisted session instead
 func (c *client) FetchSession(ctx context.Context, sessionId string) (*Person, error) {
-	response, err := requests.FetchAuthenticationSession(ctx, c.config, sessionId)
+	session, ok := c.sessions.load(sessionId)
+	if !ok {
+		return nil, errors.ErrMissingSessionHash
+	}
+
+	return c.FetchSessionFor(ctx, session)
+}
+
+// FetchSessionFor fetches the given authentication session from the Mobile-ID provider,
+// verifies the signature against the hash of the session and the certificate identity
+// against the national identity number the session was created for
+func (c *client) FetchSessionFor(ctx context.Context, session *Session) (person *Person, err error) {
+	if session == nil || session.Hash == "" {
+		return nil, errors.ErrMissingSessionHash
+	}
+	sessionId := session.Id
+
+	ctx, span := c.startSpan(ctx, "mobileid.FetchSession", attributeSessionId.String(sessionId))
+	defer func() { endSpan(span, err) }()
+
+	start := time.Now()
+	response, err := requests.FetchAuthenticationSession(ctx, c.http(), c.config, session.URL, sessionId)
+	c.metrics.PollLatency(FlowAuthentication, time.Since(start))
 	if err != nil {
+		c.recordProviderError(FlowAuthentication, err)
+		audit(ctx, c.auditSink, c.logger, AuditRecord{
+			Event:     AuditSessionPollFailed,
+			SessionId: sessionId,
+			Error:     err.Error(),
+		})
+		c.logError(ctx, "Mobile-ID authentication session poll failed", err, slog.String("session_id", sessionId))
 		return nil, err
 	}
 
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "Mobile-ID authentication session polled",
+		slog.String("session_id", sessionId),
+		slog.String("state", response.State),
+	)
+	span.SetAttributes(attributeState.String(response.State), attributeResult.String(response.Result))
+
 	switch response.State {
 	case Running:
 		return nil, errors.ErrAuthenticationIsRunning
 	case Complete:
+		c.sessions.delete(sessionId)
+		c.recordResult(FlowAuthentication, session, response.Result)
+		defer func() {
+			audit(ctx, c.auditSink, c.logger, AuditRecord{
+				Event:                  AuditSessionCompleted,
+				SessionId:              sessionId,
+				PhoneNumber:            session.PhoneNumber,
+				NationalIdentityNumber: session.NationalIdentityNumber,
+				Result:                 response.Result,
+				Error:                  errorMessage(err),
+			})
+		}()
+
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "Mobile-ID authentication session completed",
+			slog.String("session_id", sessionId),
+			slog.String("result", response.Result),
+		)
+
 		switch response.Result {
 		case OK:
-			person, err := utils.Extract(response.Cert)
+			person, err = c.verifyAuthentication(ctx, session, response)
 			if err != nil {
+				c.logError(ctx, "Mobile-ID authentication verification failed", err, slog.String("session_id", sessionId))
 				return nil, err
 			}
 
-			return &Person{
-				IdentityNumber: person.IdentityNumber,
-				PersonalCode:   person.PersonalCode,
-				FirstName:      person.FirstName,
-				LastName:       person.LastName,
-			}, nil
+			c.logger.LogAttrs(ctx, slog.LevelInfo, "Mobile-ID authentication succeeded",
+				slog.String("session_id", sessionId),
+				c.person(person),
+			)
+
+			return person, nil
 		case NOT_MID_CLIENT,
 			USER_CANCELLED,
 			SIGNATURE_HASH_MISMATCH,
@@ -82,3 +207,113 @@ func (c *client) FetchSession(ctx context.Context, sessionId string) (*Person, e
 
 	return nil, errors.ErrUnsupportedResult
 }
+
+// verifyAuthentication verifies the signature, the certificate and the identity of the completed authentication session
+func (c *client) verifyAuthentication(
+	ctx context.Context,
+	session *Session,
+	response *models.AuthenticationResponse,
+) (*Person, error) {
+	cert, err := utils.ParseCertificate(response.Cert)
+	if err != nil {
+		return nil, err
+	}
+
+	err = utils.VerifySignature(cert, session.Hash, response.Signature.Value, response.Signature.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.verifyCertificate(ctx, cert, x509.KeyUsageDigitalSignature); err != nil {
+		return nil, err
+	}
+
+	person, err := utils.ExtractFromCertificate(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.NationalIdentityNumber == "" {
+		return nil, errors.ErrMissingIdentityNumber
+	}
+
+	if person.PersonalCode != session.NationalIdentityNumber {
+		return nil, errors.ErrIdentityNumberMismatch
+	}
+
+	return &Person{
+		IdentityNumber: person.IdentityNumber,
+		PersonalCode:   person.PersonalCode,
+		FirstName:      person.FirstName,
+		LastName:       person.LastName,
+	}, nil
+}
+
+// Authenticate creates authentication session, passes the verification code to the callback
+// and polls the session until it is completed, the session timeout is exceeded or the context is done,
+// the options are applied to the created session
+func (c *client) Authenticate(
+	ctx context.Context,
+	phoneNumber, nationalIdentityNumber string,
+	onCode func(code string),
+	opts ...SessionOption,
+) (person *Person, err error) {
+	ctx, span := c.startSpan(ctx, "mobileid.Authenticate")
+	defer func() { endSpan(span, err) }()
+
+	if c.config.SessionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.SessionTimeout)
+		defer cancel()
+	}
+
+	session, err := c.CreateSession(ctx, phoneNumber, nationalIdentityNumber, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.sessions.delete(session.Id)
+	span.SetAttributes(attributeSessionId.String(session.Id))
+
+	if onCode != nil {
+		onCode(session.Code)
+	}
+
+	for polls := 1; ; polls++ {
+		span.SetAttributes(attributePolls.Int(polls))
+
+		person, err = c.FetchSessionFor(ctx, session)
+		if err != errors.ErrAuthenticationIsRunning {
+			return person, err
+		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// verifyCertificate verifies the certificate with the key usage against the trust store and its revocation status,
+// without the trust store the certificate taken from the response is not verified
+func (c *client) verifyCertificate(ctx context.Context, cert *x509.Certificate, keyUsage x509.KeyUsage) error {
+	if c.trustStore != nil {
+		if err := c.trustStore.verify(cert, keyUsage); err != nil {
+			return err
+		}
+	}
+
+	if c.ocspChecker != nil {
+		if c.trustStore == nil {
+			return errors.ErrOCSPIssuerNotFound
+		}
+
+		issuer, err := c.trustStore.Issuer(cert)
+		if err != nil {
+			return err
+		}
+
+		return c.ocspChecker.Check(ctx, cert, issuer)
+	}
+
+	return nil
+}
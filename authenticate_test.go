@@ -2,13 +2,21 @@ package mobileid
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
 
 	"github.com/tab/mobileid/internal/errors"
+	"github.com/tab/mobileid/internal/models"
+	"github.com/tab/mobileid/internal/utils"
 )
 
 func Test_CreateSession(t *testing.T) {
@@ -68,8 +76,8 @@ func Test_CreateSession(t *testing.T) {
 			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
 			defer testServer.Close()
 
-			client := NewClient()
-			client.WithRelyingPartyName("DEMO").
+			client := NewClient().
+				WithRelyingPartyName("DEMO").
 				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
 				WithURL(testServer.URL)
 
@@ -81,6 +89,13 @@ func Test_CreateSession(t *testing.T) {
 			} else {
 				assert.NotNil(t, session)
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.Id, session.Id)
+				assert.NotEmpty(t, session.Code)
+				assert.NotEmpty(t, session.Hash)
+				assert.Equal(t, "SHA512", session.HashType)
+				assert.Equal(t, tt.phoneNumber, session.PhoneNumber)
+				assert.Equal(t, tt.identity, session.NationalIdentityNumber)
+				assert.Equal(t, session.CreatedAt.Add(SessionLifetime), session.ExpiresAt)
 			}
 		})
 	}
@@ -89,9 +104,11 @@ func Test_CreateSession(t *testing.T) {
 func Test_FetchSession(t *testing.T) {
 	ctx := context.Background()
 
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+
 	tests := []struct {
 		name      string
-		before    func(w http.ResponseWriter, r *http.Request)
+		before    func(w http.ResponseWriter, r *http.Request, hash string)
 		sessionId string
 		expected  *Person
 		err       error
@@ -99,18 +116,9 @@ func Test_FetchSession(t *testing.T) {
 	}{
 		{
 			name: "Success",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
 				w.Header().Set("Content-Type", "application/json")
-				w.Write([]byte(`
-{
-	"state": "COMPLETE",
-	"result": "OK",
-	"signature": {
-		"value": "Id21arR18nvRSZe3BlJpP1KTwK/wTM3HudXEw3bu/FytpJOrk/i/Lzu+1S47evMFcBON8l4Vw9XNY8M2k9f5yA==",
-		"algorithm": "SHA512WithECEncryption"
-	},
-	"cert": "MIIDqDCCAy6gAwIBAgIQB9W11BzBABj+0d/AZx6UHzAKBggqhkjOPQQDAjBxMQswCQYDVQQGEwJFRTEbMBkGA1UECgwSU0sgSUQgU29sdXRpb25zIEFTMRcwFQYDVQRhDA5OVFJFRS0xMDc0NzAxMzEsMCoGA1UEAwwjVEVTVCBvZiBTSyBJRCBTb2x1dGlvbnMgRUlELVEgMjAyMUUwHhcNMjQwNjEyMDY0NTI4WhcNMjkwNjE2MDY0NTI3WjCBlTELMAkGA1UEBhMCRUUxLzAtBgNVBAMMJk1BUlkgw4ROTixPJ0NPTk5Fxb0txaBVU0xJSyBURVNUTlVNQkVSMSUwIwYDVQQEDBxPJ0NPTk5Fxb0txaBVU0xJSyBURVNUTlVNQkVSMRIwEAYDVQQqDAlNQVJZIMOETk4xGjAYBgNVBAUTEVBOT0VFLTUxMzA3MTQ5NTYwMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEWlV1aVSXw6WhagWmFmXE/oe+0R1xZzrHyoiVlgKpGiJ8cwIQLogRGQnWY7NwgQvRHCBmsl99bj57h7SWnd03m6OCAYEwggF9MAkGA1UdEwQCMAAwHwYDVR0jBBgwFoAUScfc7QYUosdtnKbP11L9aOXoBBQwcAYIKwYBBQUHAQEEZDBiMDMGCCsGAQUFBzAChidodHRwOi8vYy5zay5lZS9URVNUX0VJRC1RXzIwMjFFLmRlci5jcnQwKwYIKwYBBQUHMAGGH2h0dHA6Ly9haWEuZGVtby5zay5lZS9laWRxMjAyMWUweAYDVR0gBHEwbzAIBgYEAI96AQIwYwYJKwYBBAHOHxIBMFYwVAYIKwYBBQUHAgEWSGh0dHBzOi8vd3d3LnNraWRzb2x1dGlvbnMuZXUvcmVzb3VyY2VzL2NlcnRpZmljYXRpb24tcHJhY3RpY2Utc3RhdGVtZW50LzA0BgNVHR8ELTArMCmgJ6AlhiNodHRwOi8vYy5zay5lZS90ZXN0X2VpZC1xXzIwMjFlLmNybDAdBgNVHQ4EFgQUj8KjnXvGQJCRYOd5LVfPku7QsZwwDgYDVR0PAQH/BAQDAgeAMAoGCCqGSM49BAMCA2gAMGUCMQCocXWDbBnkM3WEyBdv9Vm0A1MNRv08WrR192dRBcX42Kz5oiH0SdHRJv2ffeuEeSwCMEw2tSA3ClJv233Dl7rIYU/T6UG2NQhvDD5FhnP0umZRmVfAUQ6eVcmU8AhFtNJjwg=="
-}`))
+				w.Write(identity.response(t, hash))
 			},
 			sessionId: "eb03076a-9f97-423e-af2e-b14c0a481ff9",
 			expected: &Person{
@@ -122,9 +130,44 @@ func Test_FetchSession(t *testing.T) {
 			err:   nil,
 			error: false,
 		},
+		{
+			name: "Error: Invalid signature",
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
+				hash, _ := utils.GenerateHash(utils.HashTypeSHA512)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(identity.response(t, hash))
+			},
+			sessionId: "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+			expected:  &Person{},
+			err:       errors.ErrInvalidSignature,
+			error:     true,
+		},
+		{
+			name: "Error: Unsupported signature algorithm",
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(fmt.Sprintf(`{"state": "COMPLETE", "result": "OK", "signature": {"value": "c2lnbmF0dXJl", "algorithm": "MD5WithRSAEncryption"}, "cert": "%s"}`, identity.cert)))
+			},
+			sessionId: "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+			expected:  &Person{},
+			err:       errors.ErrUnsupportedSignatureAlgorithm,
+			error:     true,
+		},
+		{
+			name: "Error: Missing session hash",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(identity.response(t, hash))
+			},
+			sessionId: "",
+			expected:  &Person{},
+			err:       errors.ErrMissingSessionHash,
+			error:     true,
+		},
 		{
 			name: "Error: Invalid certificate",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte(`
 {
@@ -134,7 +177,7 @@ func Test_FetchSession(t *testing.T) {
 		"value": "invalid-signature",
 		"algorithm": "sha256WithRSAEncryption"
 	},
-	"cert": "invalid-certificate",
+	"cert": "invalid-certificate"
 }`))
 			},
 			sessionId: "eb03076a-9f97-423e-af2e-b14c0a481ff9",
@@ -144,7 +187,7 @@ func Test_FetchSession(t *testing.T) {
 		},
 		{
 			name: "Error: Authentication is running",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte(`{"state": "RUNNING"}`))
 			},
@@ -155,7 +198,7 @@ func Test_FetchSession(t *testing.T) {
 		},
 		{
 			name: "Error: USER_CANCELLED",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte(`{"state": "COMPLETE", "result": "USER_CANCELLED"}`))
 			},
@@ -166,7 +209,7 @@ func Test_FetchSession(t *testing.T) {
 		},
 		{
 			name: "Error: TIMEOUT",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte(`{"state": "COMPLETE", "result": "TIMEOUT"}`))
 			},
@@ -177,7 +220,7 @@ func Test_FetchSession(t *testing.T) {
 		},
 		{
 			name: "Error: result UNKNOWN",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte(`{"state": "COMPLETE", "result": "UNKNOWN"}`))
 			},
@@ -188,7 +231,7 @@ func Test_FetchSession(t *testing.T) {
 		},
 		{
 			name: "Error: state UNKNOWN",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte(`{"state": "UNKNOWN"}`))
 			},
@@ -199,7 +242,7 @@ func Test_FetchSession(t *testing.T) {
 		},
 		{
 			name: "Bad Request",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusBadRequest)
 				w.Write([]byte(`{"title": "Bad Request", "status": 400}`))
@@ -211,7 +254,7 @@ func Test_FetchSession(t *testing.T) {
 		},
 		{
 			name: "Internal Server Error",
-			before: func(w http.ResponseWriter, r *http.Request) {
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusInternalServerError)
 				w.Write([]byte(`{"title": "Internal Server Error", "status": 500}`))
@@ -225,18 +268,41 @@ func Test_FetchSession(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
+			var hash string
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					var body models.AuthenticationRequest
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					hash = body.Hash
+
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+					return
+				}
+
+				tt.before(w, r, hash)
+			}))
 			defer testServer.Close()
 
-			c := NewClient()
-			c.WithRelyingPartyName("DEMO").
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
 				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
-				WithURL(testServer.URL)
+				WithURL(testServer.URL).
+				WithTrustStore(identity.trustStore(t))
+
+			_, err := c.CreateSession(ctx, "+37269930366", "51307149560")
+			assert.NoError(t, err)
 
 			session, err := c.FetchSession(ctx, tt.sessionId)
 
 			if tt.error {
 				assert.Error(t, err)
+				if _, ok := tt.err.(*Error); ok {
+					assert.Equal(t, tt.err, err)
+				} else {
+					assert.ErrorIs(t, err, tt.err)
+				}
 				assert.Nil(t, session)
 			} else {
 				assert.NotNil(t, session)
@@ -247,6 +313,476 @@ func Test_FetchSession(t *testing.T) {
 	}
 }
 
+func Test_FetchSessionFor(t *testing.T) {
+	ctx := context.Background()
+
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+	hash, err := utils.GenerateHash(utils.HashTypeSHA512)
+	assert.NoError(t, err)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(identity.response(t, hash))
+	}))
+	defer testServer.Close()
+
+	tests := []struct {
+		name     string
+		session  *Session
+		expected *Person
+		err      error
+	}{
+		{
+			name: "Success",
+			session: &Session{
+				Id:                     "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+				Hash:                   hash,
+				HashType:               utils.HashTypeSHA512,
+				NationalIdentityNumber: "51307149560",
+			},
+			expected: &Person{
+				IdentityNumber: "PNOEE-51307149560",
+				PersonalCode:   "51307149560",
+				FirstName:      "MARY ÄNN",
+				LastName:       "O'CONNEŽ-ŠUSLIK TESTNUMBER",
+			},
+			err: nil,
+		},
+		{
+			name: "Error: Identity number mismatch",
+			session: &Session{
+				Id:                     "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+				Hash:                   hash,
+				HashType:               utils.HashTypeSHA512,
+				NationalIdentityNumber: "60001017869",
+			},
+			expected: nil,
+			err:      errors.ErrIdentityNumberMismatch,
+		},
+		{
+			name: "Error: Session without identity number",
+			session: &Session{
+				Id:       "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+				Hash:     hash,
+				HashType: utils.HashTypeSHA512,
+			},
+			expected: nil,
+			err:      errors.ErrMissingIdentityNumber,
+		},
+		{
+			name: "Error: Session without hash",
+			session: &Session{
+				Id: "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+			},
+			expected: nil,
+			err:      errors.ErrMissingSessionHash,
+		},
+		{
+			name:     "Error: Nil session",
+			session:  nil,
+			expected: nil,
+			err:      errors.ErrMissingSessionHash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(identity.trustStore(t))
+
+			person, err := c.FetchSessionFor(ctx, tt.session)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, person)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, person)
+			}
+		})
+	}
+}
+
+func Test_FetchSessionFor_TrustStore(t *testing.T) {
+	ctx := context.Background()
+
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+	unknown := newTestCA(t, nil, "Unknown CA")
+
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, root, intermediate))
+	assert.NoError(t, err)
+
+	hash, err := utils.GenerateHash(utils.HashTypeSHA512)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		identity   *testIdentity
+		trustStore *TrustStore
+		err        error
+	}{
+		{
+			name:       "Success",
+			identity:   issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}),
+			trustStore: store,
+			err:        nil,
+		},
+		{
+			name:       "Success: Without trust store",
+			identity:   issueTestIdentity(t, unknown, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}),
+			trustStore: nil,
+			err:        nil,
+		},
+		{
+			name:       "Error: Certificate not trusted",
+			identity:   issueTestIdentity(t, unknown, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}),
+			trustStore: store,
+			err:        errors.ErrCertificateNotTrusted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(tt.identity.response(t, hash))
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(tt.trustStore)
+
+			person, err := c.FetchSessionFor(ctx, &Session{Id: "eb03076a-9f97-423e-af2e-b14c0a481ff9", Hash: hash, NationalIdentityNumber: "60001017869"})
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, person)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "60001017869", person.PersonalCode)
+			}
+		})
+	}
+}
+
+func Test_FetchSessionFor_OCSP(t *testing.T) {
+	ctx := context.Background()
+
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, root, intermediate))
+	assert.NoError(t, err)
+
+	hash, err := utils.GenerateHash(utils.HashTypeSHA512)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		status     int
+		trustStore *TrustStore
+		err        error
+	}{
+		{
+			name:       "Success",
+			status:     ocsp.Good,
+			trustStore: store,
+			err:        nil,
+		},
+		{
+			name:       "Error: Certificate revoked",
+			status:     ocsp.Revoked,
+			trustStore: store,
+			err:        errors.ErrCertificateRevoked,
+		},
+		{
+			name:       "Error: Without trust store",
+			status:     ocsp.Good,
+			trustStore: nil,
+			err:        errors.ErrOCSPIssuerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			responder := newTestOCSPResponder(t, intermediate, tt.status, time.Hour, &calls)
+			defer responder.Close()
+
+			identity := issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+				c.OCSPServer = []string{responder.URL}
+			})
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(identity.response(t, hash))
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(tt.trustStore).
+				WithOCSPChecker(NewOCSPChecker())
+
+			person, err := c.FetchSessionFor(ctx, &Session{Id: "eb03076a-9f97-423e-af2e-b14c0a481ff9", Hash: hash, NationalIdentityNumber: "60001017869"})
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, person)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "60001017869", person.PersonalCode)
+			}
+		})
+	}
+}
+
+func Test_Authenticate(t *testing.T) {
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+
+	tests := []struct {
+		name     string
+		running  int
+		create   int
+		result   string
+		timeout  time.Duration
+		session  time.Duration
+		expected *Person
+		err      error
+	}{
+		{
+			name:    "Success",
+			running: 2,
+			create:  http.StatusOK,
+			result:  OK,
+			timeout: 5 * time.Second,
+			expected: &Person{
+				IdentityNumber: "PNOEE-51307149560",
+				PersonalCode:   "51307149560",
+				FirstName:      "MARY ÄNN",
+				LastName:       "O'CONNEŽ-ŠUSLIK TESTNUMBER",
+			},
+			err: nil,
+		},
+		{
+			name:     "Error: USER_CANCELLED",
+			running:  1,
+			create:   http.StatusOK,
+			result:   USER_CANCELLED,
+			timeout:  5 * time.Second,
+			expected: nil,
+			err:      &Error{Code: USER_CANCELLED},
+		},
+		{
+			name:     "Error: Failed to create session",
+			create:   http.StatusUnauthorized,
+			timeout:  5 * time.Second,
+			expected: nil,
+			err:      errors.ErrMobileIdAccessForbidden,
+		},
+		{
+			name:     "Error: Context deadline exceeded",
+			running:  1000,
+			create:   http.StatusOK,
+			timeout:  50 * time.Millisecond,
+			expected: nil,
+			err:      context.DeadlineExceeded,
+		},
+		{
+			name:     "Error: Session timeout exceeded",
+			running:  1000,
+			create:   http.StatusOK,
+			timeout:  5 * time.Second,
+			session:  50 * time.Millisecond,
+			expected: nil,
+			err:      context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hash string
+			polls := 0
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.Method == http.MethodPost {
+					var body models.AuthenticationRequest
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					hash = body.Hash
+
+					w.WriteHeader(tt.create)
+					w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+					return
+				}
+
+				polls++
+				switch {
+				case polls <= tt.running:
+					time.Sleep(5 * time.Millisecond)
+					w.Write([]byte(`{"state": "RUNNING"}`))
+				case tt.result == OK:
+					w.Write(identity.response(t, hash))
+				default:
+					w.Write([]byte(fmt.Sprintf(`{"state": "COMPLETE", "result": "%s"}`, tt.result)))
+				}
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(identity.trustStore(t))
+
+			if tt.session > 0 {
+				c = c.WithSessionTimeout(tt.session)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			var code string
+			person, err := c.Authenticate(ctx, "+37269930366", "51307149560", func(value string) {
+				code = value
+			})
+
+			if tt.err != nil {
+				assert.ErrorContains(t, err, tt.err.Error())
+				assert.Nil(t, person)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, person)
+				assert.Equal(t, tt.running+1, polls)
+			}
+
+			if tt.create == http.StatusOK {
+				assert.Len(t, code, 4)
+			}
+
+			_, ok := c.(*client).sessions.load("eb03076a-9f97-423e-af2e-b14c0a481ff9")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func Test_Failover(t *testing.T) {
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+
+	var primaryRequests, secondaryPolls atomic.Int32
+	var hash string
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			var body models.AuthenticationRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			hash = body.Hash
+
+			w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+			return
+		}
+
+		secondaryPolls.Add(1)
+		w.Write(identity.response(t, hash))
+	}))
+	defer secondary.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(primary.URL).
+		WithFailoverURLs(secondary.URL).
+		WithTrustStore(identity.trustStore(t))
+
+	session, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+	assert.NoError(t, err)
+	assert.Equal(t, secondary.URL, session.URL)
+	assert.Equal(t, int32(1), primaryRequests.Load())
+
+	person, err := c.FetchSession(context.Background(), session.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "51307149560", person.PersonalCode)
+	assert.Equal(t, int32(1), secondaryPolls.Load())
+	assert.Equal(t, int32(1), primaryRequests.Load())
+}
+
+func Test_Failover_PollStaysOnSessionEndpoint(t *testing.T) {
+	var primaryPolls, secondaryPolls atomic.Int32
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryPolls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryPolls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state": "RUNNING"}`))
+	}))
+	defer secondary.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(primary.URL).
+		WithFailoverURLs(secondary.URL)
+
+	session := &Session{Id: "eb03076a-9f97-423e-af2e-b14c0a481ff9", Hash: "hash", URL: primary.URL}
+
+	_, err := c.FetchSessionFor(context.Background(), session)
+	assert.ErrorIs(t, err, ErrMobileIdProviderError)
+	assert.Equal(t, int32(1), primaryPolls.Load())
+	assert.Equal(t, int32(0), secondaryPolls.Load())
+}
+
+func Test_FetchSessionFor_UnknownSessionURL(t *testing.T) {
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+
+	var polls atomic.Int32
+	forged := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(identity.response(t, "K74MSLkafRuKZ1Ooucvh2xa4Q3nz+R/hFWIShN96SPHNcem+uQ6mFMe9kkJQqp5EaoZnJeaFpl310TmlzRgNyQ=="))
+	}))
+	defer forged.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000")
+
+	session := &Session{
+		Id:                     "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+		Hash:                   "K74MSLkafRuKZ1Ooucvh2xa4Q3nz+R/hFWIShN96SPHNcem+uQ6mFMe9kkJQqp5EaoZnJeaFpl310TmlzRgNyQ==",
+		HashType:               "SHA512",
+		NationalIdentityNumber: "51307149560",
+		URL:                    forged.URL,
+	}
+
+	person, err := c.FetchSessionFor(context.Background(), session)
+	assert.ErrorIs(t, err, ErrInvalidSessionURL)
+	assert.Nil(t, person)
+	assert.Equal(t, int32(0), polls.Load())
+}
+
 func Test_Error(t *testing.T) {
 	tests := []struct {
 		name     string
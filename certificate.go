@@ -0,0 +1,90 @@
+package mobileid
+
+import (
+	"context"
+	"crypto/x509"
+	"log/slog"
+
+	"github.com/tab/mobileid/internal/errors"
+	"github.com/tab/mobileid/internal/requests"
+	"github.com/tab/mobileid/internal/utils"
+)
+
+const (
+	NOT_FOUND  = "NOT_FOUND"
+	NOT_ACTIVE = "NOT_ACTIVE"
+)
+
+// SigningCertificate represents the signing certificate of the Mobile-ID user
+type SigningCertificate struct {
+	Certificate *x509.Certificate
+	Person      *Person
+}
+
+// FetchCertificate fetches the signing certificate of the person from the Mobile-ID provider,
+// no request is sent to the phone of the person, the certificate chain, validity and non-repudiation key usage
+// are verified against the trust store when it is set, otherwise the certificate is not verified,
+// the revocation status is checked with the OCSP checker when it is set, which requires the trust store
+func (c *client) FetchCertificate(
+	ctx context.Context,
+	phoneNumber, nationalIdentityNumber string,
+) (certificate *SigningCertificate, err error) {
+	ctx, span := c.startSpan(ctx, "mobileid.FetchCertificate")
+	defer func() { endSpan(span, err) }()
+
+	response, err := requests.FetchCertificate(ctx, c.http(), c.config, phoneNumber, nationalIdentityNumber)
+	if err != nil {
+		c.recordProviderError(FlowCertificate, err)
+		c.logError(ctx, "Mobile-ID signing certificate lookup failed", err,
+			c.pii("phone_number", phoneNumber),
+			c.pii("national_identity_number", nationalIdentityNumber),
+		)
+		return nil, err
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "Mobile-ID signing certificate fetched",
+		slog.String("result", response.Result),
+		c.pii("phone_number", phoneNumber),
+		c.pii("national_identity_number", nationalIdentityNumber),
+	)
+	span.SetAttributes(attributeResult.String(response.Result))
+	c.metrics.SessionResult(FlowCertificate, response.Result)
+
+	switch response.Result {
+	case OK:
+		cert, err := utils.ParseCertificate(response.Cert)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = c.verifyCertificate(ctx, cert, x509.KeyUsageContentCommitment); err != nil {
+			c.logError(ctx, "Mobile-ID signing certificate verification failed", err)
+			return nil, err
+		}
+
+		person, err := utils.ExtractFromCertificate(cert)
+		if err != nil {
+			return nil, err
+		}
+
+		if person.PersonalCode != nationalIdentityNumber {
+			return nil, errors.ErrIdentityNumberMismatch
+		}
+
+		return &SigningCertificate{
+			Certificate: cert,
+			Person: &Person{
+				IdentityNumber: person.IdentityNumber,
+				PersonalCode:   person.PersonalCode,
+				FirstName:      person.FirstName,
+				LastName:       person.LastName,
+			},
+		}, nil
+	case NOT_FOUND:
+		return nil, errors.ErrMobileIdCertificateNotFound
+	case NOT_ACTIVE:
+		return nil, errors.ErrMobileIdCertificateNotActive
+	default:
+		return nil, errors.ErrUnsupportedResult
+	}
+}
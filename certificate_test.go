@@ -0,0 +1,207 @@
+package mobileid
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/tab/mobileid/internal/errors"
+	"github.com/tab/mobileid/internal/models"
+)
+
+func Test_FetchCertificate(t *testing.T) {
+	ctx := context.Background()
+
+	identity := newTestSigningIdentity(t, "PNOEE-60001017869", "EID2016,TESTNUMBER")
+
+	tests := []struct {
+		name     string
+		before   func(w http.ResponseWriter, r *http.Request)
+		identity string
+		expected *Person
+		err      error
+	}{
+		{
+			name: "Success",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/certificate", r.URL.Path)
+
+				var body models.CertificateRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, "+37268000769", body.PhoneNumber)
+				assert.Equal(t, "60001017869", body.NationalIdentityNumber)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(fmt.Sprintf(`{"result": "OK", "cert": "%s"}`, identity.cert)))
+			},
+			identity: "60001017869",
+			expected: &Person{
+				IdentityNumber: "PNOEE-60001017869",
+				PersonalCode:   "60001017869",
+				FirstName:      "EID2016",
+				LastName:       "TESTNUMBER",
+			},
+			err: nil,
+		},
+		{
+			name: "Error: Identity number mismatch",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(fmt.Sprintf(`{"result": "OK", "cert": "%s"}`, identity.cert)))
+			},
+			identity: "51307149560",
+			err:      errors.ErrIdentityNumberMismatch,
+		},
+		{
+			name: "Error: NOT_FOUND",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"result": "NOT_FOUND"}`))
+			},
+			identity: "60001017869",
+			err:      errors.ErrMobileIdCertificateNotFound,
+		},
+		{
+			name: "Error: NOT_ACTIVE",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"result": "NOT_ACTIVE"}`))
+			},
+			identity: "60001017869",
+			err:      errors.ErrMobileIdCertificateNotActive,
+		},
+		{
+			name: "Error: result UNKNOWN",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"result": "UNKNOWN"}`))
+			},
+			identity: "60001017869",
+			err:      errors.ErrUnsupportedResult,
+		},
+		{
+			name: "Error: Invalid certificate",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"result": "OK", "cert": "invalid-certificate"}`))
+			},
+			identity: "60001017869",
+			err:      errors.ErrFailedToDecodeCertificate,
+		},
+		{
+			name: "Error: Bad Request",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			identity: "60001017869",
+			err:      errors.ErrMobileIdProviderPayloadError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(identity.trustStore(t))
+
+			result, err := c.FetchCertificate(ctx, "+37268000769", tt.identity)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, identity.certificate, result.Certificate)
+				assert.Equal(t, tt.expected, result.Person)
+			}
+		})
+	}
+}
+
+func Test_FetchCertificate_TrustStore(t *testing.T) {
+	ctx := context.Background()
+
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+	unknown := newTestCA(t, nil, "Unknown CA")
+
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, root, intermediate))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		issuer *testCA
+		status int
+		err    error
+	}{
+		{
+			name:   "Success",
+			issuer: intermediate,
+			status: ocsp.Good,
+			err:    nil,
+		},
+		{
+			name:   "Error: Certificate not trusted",
+			issuer: unknown,
+			status: ocsp.Good,
+			err:    errors.ErrCertificateNotTrusted,
+		},
+		{
+			name:   "Error: Certificate revoked",
+			issuer: intermediate,
+			status: ocsp.Revoked,
+			err:    errors.ErrCertificateRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			responder := newTestOCSPResponder(t, intermediate, tt.status, time.Hour, &calls)
+			defer responder.Close()
+
+			identity := issueTestIdentity(t, tt.issuer, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+				c.KeyUsage = x509.KeyUsageContentCommitment
+				c.OCSPServer = []string{responder.URL}
+			})
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(fmt.Sprintf(`{"result": "OK", "cert": "%s"}`, identity.cert)))
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(store).
+				WithOCSPChecker(NewOCSPChecker())
+
+			result, err := c.FetchCertificate(ctx, "+37268000769", "60001017869")
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, identity.certificate.Equal(result.Certificate))
+			}
+		})
+	}
+}
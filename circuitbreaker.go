@@ -0,0 +1,225 @@
+package mobileid
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failures opening the circuit
+	DefaultFailureThreshold = 5
+
+	// DefaultOpenTimeout is the default duration the circuit stays open before probing the provider
+	DefaultOpenTimeout = 30 * time.Second
+
+	// DefaultHalfOpenRequests is the default number of successful probe requests closing the circuit
+	DefaultHalfOpenRequests = 1
+)
+
+// CircuitState is the state of the circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed allows all requests to the provider
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen rejects all requests to the provider with ErrCircuitOpen
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen allows a limited number of probe requests to the provider
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker keeps a separate circuit for every endpoint of the Mobile-ID API,
+// so the failover endpoints are tried while the circuit of the failed endpoint is open
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenRequests int
+
+	mu          sync.Mutex
+	circuits    map[string]*circuit
+	currentTime func() time.Time
+}
+
+// circuit is the state of the circuit of a single endpoint
+type circuit struct {
+	state     CircuitState
+	failures  int
+	successes int
+	probes    int
+	openedAt  time.Time
+}
+
+// NewCircuitBreaker creates a new circuit breaker with the closed circuits
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: DefaultFailureThreshold,
+		openTimeout:      DefaultOpenTimeout,
+		halfOpenRequests: DefaultHalfOpenRequests,
+		circuits:         make(map[string]*circuit),
+		currentTime:      time.Now,
+	}
+}
+
+// WithFailureThreshold sets the number of consecutive failures opening the circuit
+func (b *CircuitBreaker) WithFailureThreshold(threshold int) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+
+	b.failureThreshold = threshold
+	return b
+}
+
+// WithOpenTimeout sets the duration the circuit stays open before probing the provider
+func (b *CircuitBreaker) WithOpenTimeout(timeout time.Duration) *CircuitBreaker {
+	if timeout <= 0 {
+		timeout = DefaultOpenTimeout
+	}
+
+	b.openTimeout = timeout
+	return b
+}
+
+// WithHalfOpenRequests sets the number of successful probe requests closing the circuit
+func (b *CircuitBreaker) WithHalfOpenRequests(requests int) *CircuitBreaker {
+	if requests <= 0 {
+		requests = DefaultHalfOpenRequests
+	}
+
+	b.halfOpenRequests = requests
+	return b
+}
+
+// State returns the state of the Mobile-ID API, the circuit is closed while any endpoint is closed,
+// half-open while any endpoint is probed and open when the circuits of all used endpoints are open
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := CircuitClosed
+	for _, c := range b.circuits {
+		b.advance(c)
+
+		switch c.state {
+		case CircuitClosed:
+			return CircuitClosed
+		case CircuitHalfOpen:
+			state = CircuitHalfOpen
+		case CircuitOpen:
+			if state != CircuitHalfOpen {
+				state = CircuitOpen
+			}
+		}
+	}
+
+	return state
+}
+
+// EndpointState returns the current state of the circuit of the endpoint
+func (b *CircuitBreaker) EndpointState(url string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(url)
+	b.advance(c)
+	return c.state
+}
+
+// Allow reports whether the request may be sent to the endpoint, ErrCircuitOpen is returned when the circuit
+// of the endpoint is open or all probe requests of the half-open circuit are in flight
+func (b *CircuitBreaker) Allow(url string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(url)
+	b.advance(c)
+
+	switch c.state {
+	case CircuitOpen:
+		return errors.ErrCircuitOpen
+	case CircuitHalfOpen:
+		if c.probes >= b.halfOpenRequests {
+			return errors.ErrCircuitOpen
+		}
+		c.probes++
+	}
+
+	return nil
+}
+
+// Record records the result of the allowed request to the endpoint
+func (b *CircuitBreaker) Record(url string, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(url)
+
+	switch c.state {
+	case CircuitClosed:
+		if success {
+			c.failures = 0
+			return
+		}
+
+		c.failures++
+		if c.failures >= b.failureThreshold {
+			b.open(c)
+		}
+	case CircuitHalfOpen:
+		if !success {
+			b.open(c)
+			return
+		}
+
+		c.successes++
+		if c.successes >= b.halfOpenRequests {
+			c.state = CircuitClosed
+			c.failures = 0
+		}
+	}
+}
+
+// Release releases the allowed request to the endpoint without recording its result, the request cancelled
+// by the caller tells nothing about the provider, so its probe slot of the half-open circuit is freed for the next request
+func (b *CircuitBreaker) Release(url string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(url)
+	if c.state == CircuitHalfOpen && c.probes > c.successes {
+		c.probes--
+	}
+}
+
+// circuit returns the circuit of the endpoint, the circuit of the new endpoint is closed
+func (b *CircuitBreaker) circuit(url string) *circuit {
+	c, ok := b.circuits[url]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		b.circuits[url] = c
+	}
+
+	return c
+}
+
+// open moves the circuit to the open state
+func (b *CircuitBreaker) open(c *circuit) {
+	c.state = CircuitOpen
+	c.openedAt = b.currentTime()
+	c.failures = 0
+	c.successes = 0
+	c.probes = 0
+}
+
+// advance moves the open circuit to the half-open state when the open timeout is elapsed
+func (b *CircuitBreaker) advance(c *circuit) {
+	if c.state == CircuitOpen && b.currentTime().Sub(c.openedAt) >= b.openTimeout {
+		c.state = CircuitHalfOpen
+		c.successes = 0
+		c.probes = 0
+	}
+}
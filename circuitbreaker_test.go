@@ -0,0 +1,272 @@
+package mobileid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+func Test_NewCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   func(b *CircuitBreaker)
+		expected *CircuitBreaker
+	}{
+		{
+			name:   "Default values",
+			before: func(b *CircuitBreaker) {},
+			expected: &CircuitBreaker{
+				failureThreshold: DefaultFailureThreshold,
+				openTimeout:      DefaultOpenTimeout,
+				halfOpenRequests: DefaultHalfOpenRequests,
+			},
+		},
+		{
+			name: "Success",
+			before: func(b *CircuitBreaker) {
+				b.WithFailureThreshold(3).WithOpenTimeout(10 * time.Second).WithHalfOpenRequests(2)
+			},
+			expected: &CircuitBreaker{
+				failureThreshold: 3,
+				openTimeout:      10 * time.Second,
+				halfOpenRequests: 2,
+			},
+		},
+		{
+			name: "Zero values",
+			before: func(b *CircuitBreaker) {
+				b.WithFailureThreshold(0).WithOpenTimeout(0).WithHalfOpenRequests(0)
+			},
+			expected: &CircuitBreaker{
+				failureThreshold: DefaultFailureThreshold,
+				openTimeout:      DefaultOpenTimeout,
+				halfOpenRequests: DefaultHalfOpenRequests,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker()
+			tt.before(b)
+
+			assert.Equal(t, tt.expected.failureThreshold, b.failureThreshold)
+			assert.Equal(t, tt.expected.openTimeout, b.openTimeout)
+			assert.Equal(t, tt.expected.halfOpenRequests, b.halfOpenRequests)
+			assert.Equal(t, CircuitClosed, b.State())
+		})
+	}
+}
+
+func Test_CircuitBreaker_State(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	primary := "https://mid.sk.ee/mid-api"
+
+	tests := []struct {
+		name     string
+		before   func(b *CircuitBreaker)
+		expected CircuitState
+		err      error
+	}{
+		{
+			name: "Closed: Failures below threshold",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+			},
+			expected: CircuitClosed,
+			err:      nil,
+		},
+		{
+			name: "Closed: Success resets failures",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Record(primary, true)
+				b.Record(primary, false)
+			},
+			expected: CircuitClosed,
+			err:      nil,
+		},
+		{
+			name: "Open: Failures reached threshold",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Record(primary, false)
+			},
+			expected: CircuitOpen,
+			err:      errors.ErrCircuitOpen,
+		},
+		{
+			name: "Half-open: Open timeout elapsed",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Record(primary, false)
+				now = now.Add(10 * time.Second)
+			},
+			expected: CircuitHalfOpen,
+			err:      nil,
+		},
+		{
+			name: "Half-open: Probe requests in flight",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Record(primary, false)
+				now = now.Add(10 * time.Second)
+				_ = b.Allow(primary)
+			},
+			expected: CircuitHalfOpen,
+			err:      errors.ErrCircuitOpen,
+		},
+		{
+			name: "Closed: Probe request succeeded",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Record(primary, false)
+				now = now.Add(10 * time.Second)
+				_ = b.Allow(primary)
+				b.Record(primary, true)
+			},
+			expected: CircuitClosed,
+			err:      nil,
+		},
+		{
+			name: "Open: Probe request failed",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Record(primary, false)
+				now = now.Add(10 * time.Second)
+				_ = b.Allow(primary)
+				b.Record(primary, false)
+			},
+			expected: CircuitOpen,
+			err:      errors.ErrCircuitOpen,
+		},
+		{
+			name: "Half-open: Probe request released",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Record(primary, false)
+				now = now.Add(10 * time.Second)
+				_ = b.Allow(primary)
+				b.Release(primary)
+			},
+			expected: CircuitHalfOpen,
+			err:      nil,
+		},
+		{
+			name: "Closed: Released requests are not counted",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(primary, false)
+				b.Release(primary)
+			},
+			expected: CircuitClosed,
+			err:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker().WithFailureThreshold(3).WithOpenTimeout(10 * time.Second)
+			b.currentTime = func() time.Time { return now }
+
+			tt.before(b)
+
+			assert.Equal(t, tt.expected, b.EndpointState(primary))
+			assert.Equal(t, tt.expected, b.State())
+			assert.Equal(t, tt.err, b.Allow(primary))
+			assert.NoError(t, b.Allow("https://mid2.sk.ee/mid-api"))
+		})
+	}
+}
+
+func Test_CircuitBreaker_Endpoints(t *testing.T) {
+	primary := "https://mid.sk.ee/mid-api"
+	secondary := "https://mid2.sk.ee/mid-api"
+
+	tests := []struct {
+		name     string
+		before   func(b *CircuitBreaker)
+		expected CircuitState
+	}{
+		{
+			name:     "Closed: No requests",
+			before:   func(b *CircuitBreaker) {},
+			expected: CircuitClosed,
+		},
+		{
+			name: "Closed: Secondary endpoint is closed",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(secondary, true)
+			},
+			expected: CircuitClosed,
+		},
+		{
+			name: "Open: All endpoints are open",
+			before: func(b *CircuitBreaker) {
+				b.Record(primary, false)
+				b.Record(secondary, false)
+			},
+			expected: CircuitOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker().WithFailureThreshold(1)
+
+			tt.before(b)
+
+			assert.Equal(t, tt.expected, b.State())
+		})
+	}
+}
+
+func Test_CircuitBreaker_FailFast(t *testing.T) {
+	var requests atomic.Int32
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer testServer.Close()
+
+	breaker := NewCircuitBreaker().WithFailureThreshold(2)
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithCircuitBreaker(breaker)
+
+	session := &Session{Id: "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6", Hash: "hash"}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.FetchSessionFor(context.Background(), session)
+		assert.ErrorIs(t, err, errors.ErrMobileIdProviderError)
+	}
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	_, err := c.FetchSessionFor(context.Background(), session)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	_, err = c.CreateSession(context.Background(), "+37269930366", "51307149560")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	assert.Equal(t, int32(2), requests.Load())
+}
@@ -3,111 +3,421 @@ package mobileid
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/tab/mobileid/internal/config"
-	"github.com/tab/mobileid/internal/errors"
 	"github.com/tab/mobileid/internal/requests"
 	"github.com/tab/mobileid/internal/utils"
 )
 
 const (
-	Text       = "Enter PIN1"
-	TextFormat = "GSM-7"
-	Language   = "ENG"
-	Timeout    = requests.Timeout
-	URL        = "https://tsp.demo.sk.ee/mid-api"
+	Text           = "Enter PIN1"
+	TextFormat     = "GSM-7"
+	Language       = "ENG"
+	Timeout        = requests.Timeout
+	RequestTimeout = requests.RequestTimeout
+	URL            = "https://tsp.demo.sk.ee/mid-api"
 )
 
+// RetryPolicy defines retries of the transient failures, session status requests and certificate lookups are retried
+// on network and server side errors, session creation only when the request was not sent to the provider
+type RetryPolicy = config.RetryPolicy
+
+// Middleware wraps the RoundTripper of the HTTP client, e.g. to inject headers or record requests
+type Middleware = config.Middleware
+
+// RoundTripperFunc is an adapter to use the function as http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 type Client interface {
-	CreateSession(ctx context.Context, phoneNumber, nationalIdentityNumber string) (*Session, error)
+	CreateSession(ctx context.Context, phoneNumber, nationalIdentityNumber string, opts ...SessionOption) (*Session, error)
 	FetchSession(ctx context.Context, sessionId string) (*Person, error)
+	FetchSessionFor(ctx context.Context, session *Session) (*Person, error)
+	Authenticate(ctx context.Context, phoneNumber, nationalIdentityNumber string, onCode func(code string), opts ...SessionOption) (*Person, error)
+
+	CreateSignatureSession(ctx context.Context, phoneNumber, nationalIdentityNumber string, certificate *x509.Certificate, digest []byte, hashType string) (*Session, error)
+	FetchSignatureSession(ctx context.Context, sessionId string) (*Signature, error)
+	FetchSignatureSessionFor(ctx context.Context, session *Session) (*Signature, error)
+	FetchCertificate(ctx context.Context, phoneNumber, nationalIdentityNumber string) (*SigningCertificate, error)
 
 	WithRelyingPartyName(name string) Client
 	WithRelyingPartyUUID(id string) Client
 	WithHashType(hashType string) Client
 	WithText(text string) Client
 	WithTextFormat(format string) Client
+	WithAutoTextFormat(enabled bool) Client
 	WithLanguage(language string) Client
 	WithURL(url string) Client
+	WithFailoverURLs(urls ...string) Client
 	WithTimeout(timeout time.Duration) Client
+	WithRequestTimeout(timeout time.Duration) Client
+	WithSessionTimeout(timeout time.Duration) Client
 	WithTLSConfig(tlsConfig *tls.Config) Client
+	WithMaxIdleConns(n int) Client
+	WithMaxIdleConnsPerHost(n int) Client
+	WithMaxConnsPerHost(n int) Client
+	WithIdleConnTimeout(timeout time.Duration) Client
+	WithKeepAlive(keepAlive time.Duration) Client
+	WithHTTPClient(httpClient *http.Client) Client
+	WithTransport(transport http.RoundTripper) Client
+	WithMiddleware(middlewares ...Middleware) Client
+	WithRetryPolicy(policy RetryPolicy) Client
+	WithLogger(logger *slog.Logger) Client
+	WithLogPII(enabled bool) Client
+	WithRedactionKey(key []byte) Client
+	WithTracerProvider(provider trace.TracerProvider) Client
+	WithMetrics(metrics Metrics) Client
+	WithAuditSink(sink AuditSink) Client
+	WithCircuitBreaker(breaker *CircuitBreaker) Client
+	WithTrustStore(trustStore *TrustStore) Client
+	WithOCSPChecker(checker *OCSPChecker) Client
 
 	Validate() error
 }
 
 type client struct {
-	config *config.Config
+	config       *config.Config
+	sessions     *sessions
+	trustStore   *TrustStore
+	ocspChecker  *OCSPChecker
+	logger       *slog.Logger
+	logPII       bool
+	redactionKey []byte
+
+	tracerProvider trace.TracerProvider
+	metrics        Metrics
+	auditSink      AuditSink
+
+	transport *transport
+}
+
+// transport holds the HTTP client built from the transport settings of the config,
+// it is shared by the derived clients until their transport settings are changed
+type transport struct {
+	mu         sync.Mutex
+	httpClient *resty.Client
 }
 
 func NewClient() Client {
 	cfg := &config.Config{
-		HashType:   utils.HashTypeSHA512,
-		Text:       Text,
-		TextFormat: TextFormat,
-		Language:   Language,
-		URL:        URL,
-		Timeout:    Timeout,
+		HashType:       utils.HashTypeSHA512,
+		Text:           Text,
+		TextFormat:     TextFormat,
+		Language:       Language,
+		URL:            URL,
+		Timeout:        Timeout,
+		RequestTimeout: RequestTimeout,
+		SessionTimeout: DefaultSessionTimeout,
 	}
 
 	return &client{
-		config: cfg,
+		config:       cfg,
+		sessions:     newSessions(),
+		logger:       discardLogger,
+		redactionKey: redactionKey,
+		metrics:      noopMetrics{},
+		auditSink:    noopAuditSink{},
+		transport:    &transport{},
 	}
 }
 
 func (c *client) WithRelyingPartyName(name string) Client {
-	c.config.RelyingPartyName = name
-	return c
+	return c.derive(func(d *client) {
+		d.config.RelyingPartyName = name
+	})
 }
 
 func (c *client) WithRelyingPartyUUID(id string) Client {
-	c.config.RelyingPartyUUID = id
-	return c
+	return c.derive(func(d *client) {
+		d.config.RelyingPartyUUID = id
+	})
 }
 
 func (c *client) WithHashType(hashType string) Client {
-	c.config.HashType = hashType
-	return c
+	return c.derive(func(d *client) {
+		d.config.HashType = hashType
+	})
 }
 
 func (c *client) WithText(text string) Client {
-	c.config.Text = text
-	return c
+	return c.derive(func(d *client) {
+		d.config.Text = text
+	})
 }
 
 func (c *client) WithTextFormat(format string) Client {
-	c.config.TextFormat = format
-	return c
+	return c.derive(func(d *client) {
+		d.config.TextFormat = format
+	})
+}
+
+// WithAutoTextFormat enables switching of the GSM-7 text format to UCS-2 when the text contains characters
+// outside the GSM-7 alphabet, e.g. Estonian õ, Russian or Lithuanian letters
+func (c *client) WithAutoTextFormat(enabled bool) Client {
+	return c.derive(func(d *client) {
+		d.config.AutoTextFormat = enabled
+	})
 }
 
 func (c *client) WithLanguage(language string) Client {
-	c.config.Language = language
-	return c
+	return c.derive(func(d *client) {
+		d.config.Language = language
+	})
 }
 
 func (c *client) WithURL(url string) Client {
-	c.config.URL = url
-	return c
+	return c.derive(func(d *client) {
+		d.config.URL = url
+	})
+}
+
+// WithFailoverURLs sets the secondary endpoints in the order they are tried when the primary URL fails,
+// session creation fails over only when the request is not delivered (DNS or dial error) or on 503 response,
+// so the user is not prompted twice, the signing certificate lookup fails over on any network error,
+// including the timeout, and on 5xx or 429 response, the endpoints with open circuit are skipped,
+// polling of the session stays on the endpoint the session is created with
+func (c *client) WithFailoverURLs(urls ...string) Client {
+	return c.derive(func(d *client) {
+		d.config.FailoverURLs = slices.Clone(urls)
+		if len(urls) > 0 && d.config.EndpointHealth == nil {
+			d.config.EndpointHealth = requests.NewEndpointHealth(requests.EndpointCooldown)
+		}
+	})
 }
 
+// WithTimeout sets the long-poll duration of the session status request,
+// the duration is limited by the remaining context deadline
 func (c *client) WithTimeout(timeout time.Duration) Client {
-	c.config.Timeout = timeout
-	return c
+	return c.derive(func(d *client) {
+		d.config.Timeout = timeout
+	})
+}
+
+// WithRequestTimeout sets the network timeout of a single request, it is added on top of the long-poll duration
+func (c *client) WithRequestTimeout(timeout time.Duration) Client {
+	return c.derive(func(d *client) {
+		d.config.RequestTimeout = timeout
+	})
+}
+
+// WithSessionTimeout sets the overall deadline of polling the session in Authenticate,
+// it must not exceed SessionLifetime the created session is kept by the client for
+func (c *client) WithSessionTimeout(timeout time.Duration) Client {
+	return c.derive(func(d *client) {
+		d.config.SessionTimeout = timeout
+	})
 }
 
 func (c *client) WithTLSConfig(tlsConfig *tls.Config) Client {
-	c.config.TLSConfig = tlsConfig
-	return c
+	return c.deriveTransport(func(d *client) {
+		d.config.TLSConfig = tlsConfig
+	})
 }
 
-func (c *client) Validate() error {
-	if c.config.RelyingPartyName == "" {
-		return errors.ErrMissingRelyingPartyName
+// WithMaxIdleConns sets the maximum number of idle connections of the shared transport
+func (c *client) WithMaxIdleConns(n int) Client {
+	return c.deriveTransport(func(d *client) {
+		d.config.MaxIdleConns = n
+	})
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections per host of the shared transport
+func (c *client) WithMaxIdleConnsPerHost(n int) Client {
+	return c.deriveTransport(func(d *client) {
+		d.config.MaxIdleConnsPerHost = n
+	})
+}
+
+// WithMaxConnsPerHost sets the maximum number of connections per host of the shared transport, zero means no limit
+func (c *client) WithMaxConnsPerHost(n int) Client {
+	return c.deriveTransport(func(d *client) {
+		d.config.MaxConnsPerHost = n
+	})
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept in the pool
+func (c *client) WithIdleConnTimeout(timeout time.Duration) Client {
+	return c.deriveTransport(func(d *client) {
+		d.config.IdleConnTimeout = timeout
+	})
+}
+
+// WithKeepAlive sets the TCP keep-alive period of the connections
+func (c *client) WithKeepAlive(keepAlive time.Duration) Client {
+	return c.deriveTransport(func(d *client) {
+		d.config.KeepAlive = keepAlive
+	})
+}
+
+func (c *client) WithTrustStore(trustStore *TrustStore) Client {
+	return c.derive(func(d *client) {
+		d.trustStore = trustStore
+	})
+}
+
+func (c *client) WithOCSPChecker(checker *OCSPChecker) Client {
+	return c.derive(func(d *client) {
+		d.ocspChecker = checker
+	})
+}
+
+// WithHTTPClient sets the HTTP client the requests are sent with, its transport replaces the built-in one
+// and the connection pool and TLS options are not applied to it, the built-in transport is used when it has none.
+// The timeout of the HTTP client is ignored, the requests are limited by the request timeout and the long-poll duration
+func (c *client) WithHTTPClient(httpClient *http.Client) Client {
+	return c.deriveTransport(func(d *client) {
+		d.config.HTTPClient = httpClient
+	})
+}
+
+// WithTransport sets the transport the requests are sent with, the connection pool and TLS options are not applied
+func (c *client) WithTransport(transport http.RoundTripper) Client {
+	return c.deriveTransport(func(d *client) {
+		d.config.Transport = transport
+	})
+}
+
+// WithMiddleware appends the middlewares wrapping the transport, the first middleware is the outermost one
+func (c *client) WithMiddleware(middlewares ...Middleware) Client {
+	return c.deriveTransport(func(d *client) {
+		for _, middleware := range middlewares {
+			if middleware != nil {
+				d.config.Middlewares = append(d.config.Middlewares, middleware)
+			}
+		}
+	})
+}
+
+// WithRetryPolicy sets the retry policy of the transient failures, retries are disabled by default
+func (c *client) WithRetryPolicy(policy RetryPolicy) Client {
+	return c.derive(func(d *client) {
+		d.config.RetryPolicy = policy
+	})
+}
+
+// WithLogger sets the logger of the client, nothing is logged by default
+func (c *client) WithLogger(logger *slog.Logger) Client {
+	if logger == nil {
+		logger = discardLogger
+	}
+
+	return c.derive(func(d *client) {
+		d.logger = logger
+	})
+}
+
+// WithLogPII enables logging of phone numbers, national identity numbers and names in full,
+// otherwise they are replaced with their keyed hash
+func (c *client) WithLogPII(enabled bool) Client {
+	return c.derive(func(d *client) {
+		d.logPII = enabled
+	})
+}
+
+// WithRedactionKey sets the secret key the personal data is hashed with in the logs,
+// the same key correlates the log records of several processes, a random key of the process is used by default
+func (c *client) WithRedactionKey(key []byte) Client {
+	if len(key) == 0 {
+		key = redactionKey
+	}
+
+	key = append([]byte(nil), key...)
+	return c.derive(func(d *client) {
+		d.redactionKey = key
+	})
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider, the global provider is used by default
+func (c *client) WithTracerProvider(provider trace.TracerProvider) Client {
+	return c.derive(func(d *client) {
+		d.tracerProvider = provider
+	})
+}
+
+// WithMetrics sets the metrics of the client, nothing is recorded by default
+func (c *client) WithMetrics(metrics Metrics) Client {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	return c.derive(func(d *client) {
+		d.metrics = metrics
+	})
+}
+
+// WithAuditSink sets the audit sink of the authentication events, nothing is recorded by default
+func (c *client) WithAuditSink(sink AuditSink) Client {
+	if sink == nil {
+		sink = noopAuditSink{}
 	}
 
-	if c.config.RelyingPartyUUID == "" {
-		return errors.ErrMissingRelyingPartyUUID
+	return c.derive(func(d *client) {
+		d.auditSink = sink
+	})
+}
+
+// WithCircuitBreaker sets the circuit breaker guarding the requests to the Mobile-ID API
+func (c *client) WithCircuitBreaker(breaker *CircuitBreaker) Client {
+	return c.derive(func(d *client) {
+		if breaker == nil {
+			d.config.CircuitBreaker = nil
+			return
+		}
+
+		d.config.CircuitBreaker = breaker
+	})
+}
+
+// derive returns a new client with a copy of the config changed by apply, the client is not modified.
+// The derived client shares the session store, the HTTP client, the circuit breaker and the endpoint health
+// with the client, so sessions created by one client can be fetched by another
+func (c *client) derive(apply func(d *client)) Client {
+	cfg := *c.config
+	cfg.FailoverURLs = slices.Clone(c.config.FailoverURLs)
+	cfg.Middlewares = slices.Clone(c.config.Middlewares)
+
+	d := *c
+	d.config = &cfg
+	apply(&d)
+
+	return &d
+}
+
+// deriveTransport returns a new client like derive, the derived client builds its own HTTP client
+// with the changed transport settings
+func (c *client) deriveTransport(apply func(d *client)) Client {
+	return c.derive(func(d *client) {
+		d.transport = &transport{}
+		apply(d)
+	})
+}
+
+// http returns the shared HTTP client, the client is built once from the config and reused for all requests
+func (c *client) http() *resty.Client {
+	c.transport.mu.Lock()
+	defer c.transport.mu.Unlock()
+
+	if c.transport.httpClient == nil {
+		c.transport.httpClient = requests.NewHTTPClient(c.config)
 	}
 
-	return nil
+	return c.transport.httpClient
+}
+
+// Validate validates every field of the client configuration, the ValidationError lists all invalid fields
+func (c *client) Validate() error {
+	return validateConfig(c.config)
 }
@@ -12,9 +12,13 @@ package mobileid
 import (
 	context "context"
 	tls "crypto/tls"
+	x509 "crypto/x509"
+	slog "log/slog"
+	http "net/http"
 	reflect "reflect"
 	time "time"
 
+	trace "go.opentelemetry.io/otel/trace"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,19 +46,74 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// Authenticate mocks base method.
+func (m *MockClient) Authenticate(ctx context.Context, phoneNumber, nationalIdentityNumber string, onCode func(string), opts ...SessionOption) (*Person, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, phoneNumber, nationalIdentityNumber, onCode}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Authenticate", varargs...)
+	ret0, _ := ret[0].(*Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockClientMockRecorder) Authenticate(ctx, phoneNumber, nationalIdentityNumber, onCode any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, phoneNumber, nationalIdentityNumber, onCode}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockClient)(nil).Authenticate), varargs...)
+}
+
 // CreateSession mocks base method.
-func (m *MockClient) CreateSession(ctx context.Context, phoneNumber, nationalIdentityNumber string) (*Session, error) {
+func (m *MockClient) CreateSession(ctx context.Context, phoneNumber, nationalIdentityNumber string, opts ...SessionOption) (*Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", ctx, phoneNumber, nationalIdentityNumber)
+	varargs := []any{ctx, phoneNumber, nationalIdentityNumber}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateSession", varargs...)
 	ret0, _ := ret[0].(*Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSession indicates an expected call of CreateSession.
-func (mr *MockClientMockRecorder) CreateSession(ctx, phoneNumber, nationalIdentityNumber any) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateSession(ctx, phoneNumber, nationalIdentityNumber any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockClient)(nil).CreateSession), ctx, phoneNumber, nationalIdentityNumber)
+	varargs := append([]any{ctx, phoneNumber, nationalIdentityNumber}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockClient)(nil).CreateSession), varargs...)
+}
+
+// CreateSignatureSession mocks base method.
+func (m *MockClient) CreateSignatureSession(ctx context.Context, phoneNumber, nationalIdentityNumber string, certificate *x509.Certificate, digest []byte, hashType string) (*Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSignatureSession", ctx, phoneNumber, nationalIdentityNumber, certificate, digest, hashType)
+	ret0, _ := ret[0].(*Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSignatureSession indicates an expected call of CreateSignatureSession.
+func (mr *MockClientMockRecorder) CreateSignatureSession(ctx, phoneNumber, nationalIdentityNumber, certificate, digest, hashType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSignatureSession", reflect.TypeOf((*MockClient)(nil).CreateSignatureSession), ctx, phoneNumber, nationalIdentityNumber, certificate, digest, hashType)
+}
+
+// FetchCertificate mocks base method.
+func (m *MockClient) FetchCertificate(ctx context.Context, phoneNumber, nationalIdentityNumber string) (*SigningCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchCertificate", ctx, phoneNumber, nationalIdentityNumber)
+	ret0, _ := ret[0].(*SigningCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchCertificate indicates an expected call of FetchCertificate.
+func (mr *MockClientMockRecorder) FetchCertificate(ctx, phoneNumber, nationalIdentityNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCertificate", reflect.TypeOf((*MockClient)(nil).FetchCertificate), ctx, phoneNumber, nationalIdentityNumber)
 }
 
 // FetchSession mocks base method.
@@ -72,6 +131,51 @@ func (mr *MockClientMockRecorder) FetchSession(ctx, sessionId any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSession", reflect.TypeOf((*MockClient)(nil).FetchSession), ctx, sessionId)
 }
 
+// FetchSessionFor mocks base method.
+func (m *MockClient) FetchSessionFor(ctx context.Context, session *Session) (*Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchSessionFor", ctx, session)
+	ret0, _ := ret[0].(*Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSessionFor indicates an expected call of FetchSessionFor.
+func (mr *MockClientMockRecorder) FetchSessionFor(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSessionFor", reflect.TypeOf((*MockClient)(nil).FetchSessionFor), ctx, session)
+}
+
+// FetchSignatureSession mocks base method.
+func (m *MockClient) FetchSignatureSession(ctx context.Context, sessionId string) (*Signature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchSignatureSession", ctx, sessionId)
+	ret0, _ := ret[0].(*Signature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSignatureSession indicates an expected call of FetchSignatureSession.
+func (mr *MockClientMockRecorder) FetchSignatureSession(ctx, sessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSignatureSession", reflect.TypeOf((*MockClient)(nil).FetchSignatureSession), ctx, sessionId)
+}
+
+// FetchSignatureSessionFor mocks base method.
+func (m *MockClient) FetchSignatureSessionFor(ctx context.Context, session *Session) (*Signature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchSignatureSessionFor", ctx, session)
+	ret0, _ := ret[0].(*Signature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSignatureSessionFor indicates an expected call of FetchSignatureSessionFor.
+func (mr *MockClientMockRecorder) FetchSignatureSessionFor(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSignatureSessionFor", reflect.TypeOf((*MockClient)(nil).FetchSignatureSessionFor), ctx, session)
+}
+
 // Validate mocks base method.
 func (m *MockClient) Validate() error {
 	m.ctrl.T.Helper()
@@ -86,6 +190,80 @@ func (mr *MockClientMockRecorder) Validate() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockClient)(nil).Validate))
 }
 
+// WithAuditSink mocks base method.
+func (m *MockClient) WithAuditSink(sink AuditSink) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithAuditSink", sink)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithAuditSink indicates an expected call of WithAuditSink.
+func (mr *MockClientMockRecorder) WithAuditSink(sink any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithAuditSink", reflect.TypeOf((*MockClient)(nil).WithAuditSink), sink)
+}
+
+// WithAutoTextFormat mocks base method.
+func (m *MockClient) WithAutoTextFormat(enabled bool) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithAutoTextFormat", enabled)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithAutoTextFormat indicates an expected call of WithAutoTextFormat.
+func (mr *MockClientMockRecorder) WithAutoTextFormat(enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithAutoTextFormat", reflect.TypeOf((*MockClient)(nil).WithAutoTextFormat), enabled)
+}
+
+// WithCircuitBreaker mocks base method.
+func (m *MockClient) WithCircuitBreaker(breaker *CircuitBreaker) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithCircuitBreaker", breaker)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithCircuitBreaker indicates an expected call of WithCircuitBreaker.
+func (mr *MockClientMockRecorder) WithCircuitBreaker(breaker any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithCircuitBreaker", reflect.TypeOf((*MockClient)(nil).WithCircuitBreaker), breaker)
+}
+
+// WithFailoverURLs mocks base method.
+func (m *MockClient) WithFailoverURLs(urls ...string) Client {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range urls {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithFailoverURLs", varargs...)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithFailoverURLs indicates an expected call of WithFailoverURLs.
+func (mr *MockClientMockRecorder) WithFailoverURLs(urls ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithFailoverURLs", reflect.TypeOf((*MockClient)(nil).WithFailoverURLs), urls...)
+}
+
+// WithHTTPClient mocks base method.
+func (m *MockClient) WithHTTPClient(httpClient *http.Client) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithHTTPClient", httpClient)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithHTTPClient indicates an expected call of WithHTTPClient.
+func (mr *MockClientMockRecorder) WithHTTPClient(httpClient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithHTTPClient", reflect.TypeOf((*MockClient)(nil).WithHTTPClient), httpClient)
+}
+
 // WithHashType mocks base method.
 func (m *MockClient) WithHashType(hashType string) Client {
 	m.ctrl.T.Helper()
@@ -100,6 +278,34 @@ func (mr *MockClientMockRecorder) WithHashType(hashType any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithHashType", reflect.TypeOf((*MockClient)(nil).WithHashType), hashType)
 }
 
+// WithIdleConnTimeout mocks base method.
+func (m *MockClient) WithIdleConnTimeout(timeout time.Duration) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithIdleConnTimeout", timeout)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithIdleConnTimeout indicates an expected call of WithIdleConnTimeout.
+func (mr *MockClientMockRecorder) WithIdleConnTimeout(timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithIdleConnTimeout", reflect.TypeOf((*MockClient)(nil).WithIdleConnTimeout), timeout)
+}
+
+// WithKeepAlive mocks base method.
+func (m *MockClient) WithKeepAlive(keepAlive time.Duration) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithKeepAlive", keepAlive)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithKeepAlive indicates an expected call of WithKeepAlive.
+func (mr *MockClientMockRecorder) WithKeepAlive(keepAlive any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithKeepAlive", reflect.TypeOf((*MockClient)(nil).WithKeepAlive), keepAlive)
+}
+
 // WithLanguage mocks base method.
 func (m *MockClient) WithLanguage(language string) Client {
 	m.ctrl.T.Helper()
@@ -114,6 +320,136 @@ func (mr *MockClientMockRecorder) WithLanguage(language any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLanguage", reflect.TypeOf((*MockClient)(nil).WithLanguage), language)
 }
 
+// WithLogPII mocks base method.
+func (m *MockClient) WithLogPII(enabled bool) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithLogPII", enabled)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithLogPII indicates an expected call of WithLogPII.
+func (mr *MockClientMockRecorder) WithLogPII(enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLogPII", reflect.TypeOf((*MockClient)(nil).WithLogPII), enabled)
+}
+
+// WithLogger mocks base method.
+func (m *MockClient) WithLogger(logger *slog.Logger) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithLogger", logger)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithLogger indicates an expected call of WithLogger.
+func (mr *MockClientMockRecorder) WithLogger(logger any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLogger", reflect.TypeOf((*MockClient)(nil).WithLogger), logger)
+}
+
+// WithMaxConnsPerHost mocks base method.
+func (m *MockClient) WithMaxConnsPerHost(n int) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMaxConnsPerHost", n)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithMaxConnsPerHost indicates an expected call of WithMaxConnsPerHost.
+func (mr *MockClientMockRecorder) WithMaxConnsPerHost(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMaxConnsPerHost", reflect.TypeOf((*MockClient)(nil).WithMaxConnsPerHost), n)
+}
+
+// WithMaxIdleConns mocks base method.
+func (m *MockClient) WithMaxIdleConns(n int) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMaxIdleConns", n)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithMaxIdleConns indicates an expected call of WithMaxIdleConns.
+func (mr *MockClientMockRecorder) WithMaxIdleConns(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMaxIdleConns", reflect.TypeOf((*MockClient)(nil).WithMaxIdleConns), n)
+}
+
+// WithMaxIdleConnsPerHost mocks base method.
+func (m *MockClient) WithMaxIdleConnsPerHost(n int) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMaxIdleConnsPerHost", n)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithMaxIdleConnsPerHost indicates an expected call of WithMaxIdleConnsPerHost.
+func (mr *MockClientMockRecorder) WithMaxIdleConnsPerHost(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMaxIdleConnsPerHost", reflect.TypeOf((*MockClient)(nil).WithMaxIdleConnsPerHost), n)
+}
+
+// WithMetrics mocks base method.
+func (m *MockClient) WithMetrics(metrics Metrics) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMetrics", metrics)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithMetrics indicates an expected call of WithMetrics.
+func (mr *MockClientMockRecorder) WithMetrics(metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMetrics", reflect.TypeOf((*MockClient)(nil).WithMetrics), metrics)
+}
+
+// WithMiddleware mocks base method.
+func (m *MockClient) WithMiddleware(middlewares ...Middleware) Client {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range middlewares {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithMiddleware", varargs...)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithMiddleware indicates an expected call of WithMiddleware.
+func (mr *MockClientMockRecorder) WithMiddleware(middlewares ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMiddleware", reflect.TypeOf((*MockClient)(nil).WithMiddleware), middlewares...)
+}
+
+// WithOCSPChecker mocks base method.
+func (m *MockClient) WithOCSPChecker(checker *OCSPChecker) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithOCSPChecker", checker)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithOCSPChecker indicates an expected call of WithOCSPChecker.
+func (mr *MockClientMockRecorder) WithOCSPChecker(checker any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithOCSPChecker", reflect.TypeOf((*MockClient)(nil).WithOCSPChecker), checker)
+}
+
+// WithRedactionKey mocks base method.
+func (m *MockClient) WithRedactionKey(key []byte) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithRedactionKey", key)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithRedactionKey indicates an expected call of WithRedactionKey.
+func (mr *MockClientMockRecorder) WithRedactionKey(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRedactionKey", reflect.TypeOf((*MockClient)(nil).WithRedactionKey), key)
+}
+
 // WithRelyingPartyName mocks base method.
 func (m *MockClient) WithRelyingPartyName(name string) Client {
 	m.ctrl.T.Helper()
@@ -142,6 +478,48 @@ func (mr *MockClientMockRecorder) WithRelyingPartyUUID(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRelyingPartyUUID", reflect.TypeOf((*MockClient)(nil).WithRelyingPartyUUID), id)
 }
 
+// WithRequestTimeout mocks base method.
+func (m *MockClient) WithRequestTimeout(timeout time.Duration) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithRequestTimeout", timeout)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithRequestTimeout indicates an expected call of WithRequestTimeout.
+func (mr *MockClientMockRecorder) WithRequestTimeout(timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRequestTimeout", reflect.TypeOf((*MockClient)(nil).WithRequestTimeout), timeout)
+}
+
+// WithRetryPolicy mocks base method.
+func (m *MockClient) WithRetryPolicy(policy RetryPolicy) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithRetryPolicy", policy)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithRetryPolicy indicates an expected call of WithRetryPolicy.
+func (mr *MockClientMockRecorder) WithRetryPolicy(policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRetryPolicy", reflect.TypeOf((*MockClient)(nil).WithRetryPolicy), policy)
+}
+
+// WithSessionTimeout mocks base method.
+func (m *MockClient) WithSessionTimeout(timeout time.Duration) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithSessionTimeout", timeout)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithSessionTimeout indicates an expected call of WithSessionTimeout.
+func (mr *MockClientMockRecorder) WithSessionTimeout(timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithSessionTimeout", reflect.TypeOf((*MockClient)(nil).WithSessionTimeout), timeout)
+}
+
 // WithTLSConfig mocks base method.
 func (m *MockClient) WithTLSConfig(tlsConfig *tls.Config) Client {
 	m.ctrl.T.Helper()
@@ -198,6 +576,48 @@ func (mr *MockClientMockRecorder) WithTimeout(timeout any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTimeout", reflect.TypeOf((*MockClient)(nil).WithTimeout), timeout)
 }
 
+// WithTracerProvider mocks base method.
+func (m *MockClient) WithTracerProvider(provider trace.TracerProvider) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTracerProvider", provider)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithTracerProvider indicates an expected call of WithTracerProvider.
+func (mr *MockClientMockRecorder) WithTracerProvider(provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTracerProvider", reflect.TypeOf((*MockClient)(nil).WithTracerProvider), provider)
+}
+
+// WithTransport mocks base method.
+func (m *MockClient) WithTransport(transport http.RoundTripper) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransport", transport)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithTransport indicates an expected call of WithTransport.
+func (mr *MockClientMockRecorder) WithTransport(transport any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransport", reflect.TypeOf((*MockClient)(nil).WithTransport), transport)
+}
+
+// WithTrustStore mocks base method.
+func (m *MockClient) WithTrustStore(trustStore *TrustStore) Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTrustStore", trustStore)
+	ret0, _ := ret[0].(Client)
+	return ret0
+}
+
+// WithTrustStore indicates an expected call of WithTrustStore.
+func (mr *MockClientMockRecorder) WithTrustStore(trustStore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTrustStore", reflect.TypeOf((*MockClient)(nil).WithTrustStore), trustStore)
+}
+
 // WithURL mocks base method.
 func (m *MockClient) WithURL(url string) Client {
 	m.ctrl.T.Helper()
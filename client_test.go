@@ -1,6 +1,16 @@
 package mobileid
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,13 +27,13 @@ func Test_NewClient(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		before   func(c Client)
+		before   func(c Client) Client
 		expected result
 	}{
 		{
 			name: "Success",
-			before: func(c Client) {
-				c.
+			before: func(c Client) Client {
+				return c.
 					WithRelyingPartyName("DEMO").
 					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
 					WithHashType("SHA512").
@@ -43,13 +53,15 @@ func Test_NewClient(t *testing.T) {
 					Language:         "ENG",
 					URL:              "https://tsp.demo.sk.ee/mid-api",
 					Timeout:          60 * time.Second,
+					RequestTimeout:   30 * time.Second,
+					SessionTimeout:   2 * time.Minute,
 				},
 			},
 		},
 		{
 			name: "Default values",
-			before: func(c Client) {
-				c.
+			before: func(c Client) Client {
+				return c.
 					WithRelyingPartyName("DEMO").
 					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000")
 			},
@@ -63,13 +75,15 @@ func Test_NewClient(t *testing.T) {
 					Language:         "ENG",
 					URL:              "https://tsp.demo.sk.ee/mid-api",
 					Timeout:          60 * time.Second,
+					RequestTimeout:   30 * time.Second,
+					SessionTimeout:   2 * time.Minute,
 				},
 			},
 		},
 		{
 			name: "Error: Missing relying party name",
-			before: func(c Client) {
-				c.WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000")
+			before: func(c Client) Client {
+				return c.WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000")
 			},
 			expected: result{
 				config: &config.Config{
@@ -81,13 +95,15 @@ func Test_NewClient(t *testing.T) {
 					Language:         "ENG",
 					URL:              "https://tsp.demo.sk.ee/mid-api",
 					Timeout:          60 * time.Second,
+					RequestTimeout:   30 * time.Second,
+					SessionTimeout:   2 * time.Minute,
 				},
 			},
 		},
 		{
 			name: "Error: Missing relying party UUID",
-			before: func(c Client) {
-				c.WithRelyingPartyName("DEMO")
+			before: func(c Client) Client {
+				return c.WithRelyingPartyName("DEMO")
 			},
 			expected: result{
 				config: &config.Config{
@@ -99,6 +115,8 @@ func Test_NewClient(t *testing.T) {
 					Language:         "ENG",
 					URL:              "https://tsp.demo.sk.ee/mid-api",
 					Timeout:          60 * time.Second,
+					RequestTimeout:   30 * time.Second,
+					SessionTimeout:   2 * time.Minute,
 				},
 			},
 		},
@@ -107,7 +125,7 @@ func Test_NewClient(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := NewClient()
-			tt.before(c)
+			c = tt.before(c)
 
 			clientImpl := c.(*client)
 
@@ -320,6 +338,37 @@ func Test_WithURL(t *testing.T) {
 	}
 }
 
+func Test_WithFailoverURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    []string
+		expected []string
+		health   bool
+	}{
+		{
+			name:     "Success",
+			param:    []string{"https://mid.sk.ee/mid-api", "https://mid2.sk.ee/mid-api"},
+			expected: []string{"https://mid.sk.ee/mid-api", "https://mid2.sk.ee/mid-api"},
+			health:   true,
+		},
+		{
+			name:     "Empty",
+			param:    nil,
+			expected: nil,
+			health:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithFailoverURLs(tt.param...)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.config.FailoverURLs)
+			assert.Equal(t, tt.health, clientImpl.config.EndpointHealth != nil)
+		})
+	}
+}
+
 func Test_WithTimeout(t *testing.T) {
 	c := NewClient()
 
@@ -349,6 +398,64 @@ func Test_WithTimeout(t *testing.T) {
 	}
 }
 
+func Test_WithRequestTimeout(t *testing.T) {
+	c := NewClient()
+
+	tests := []struct {
+		name     string
+		param    time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "Success",
+			param:    30 * time.Second,
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "Zero",
+			param:    0,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c = c.WithRequestTimeout(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.config.RequestTimeout)
+		})
+	}
+}
+
+func Test_WithSessionTimeout(t *testing.T) {
+	c := NewClient()
+
+	tests := []struct {
+		name     string
+		param    time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "Success",
+			param:    2 * time.Minute,
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "Zero",
+			param:    0,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c = c.WithSessionTimeout(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.config.SessionTimeout)
+		})
+	}
+}
+
 func TestClient_WithTLSConfig(t *testing.T) {
 	manager, err := NewCertificateManager("./certs")
 	assert.NoError(t, err)
@@ -361,13 +468,13 @@ func TestClient_WithTLSConfig(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		before   func(c Client)
+		before   func(c Client) Client
 		expected result
 	}{
 		{
 			name: "Success",
-			before: func(c Client) {
-				c.WithRelyingPartyName("DEMO").
+			before: func(c Client) Client {
+				return c.WithRelyingPartyName("DEMO").
 					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
 					WithHashType("SHA512").
 					WithText("Enter PIN1").
@@ -387,14 +494,16 @@ func TestClient_WithTLSConfig(t *testing.T) {
 					Language:         "ENG",
 					URL:              "https://tsp.demo.sk.ee/mid-api",
 					Timeout:          60 * time.Second,
+					RequestTimeout:   30 * time.Second,
+					SessionTimeout:   2 * time.Minute,
 					TLSConfig:        tlsConfig,
 				},
 			},
 		},
 		{
 			name: "Without TLS Config",
-			before: func(c Client) {
-				c.
+			before: func(c Client) Client {
+				return c.
 					WithRelyingPartyName("DEMO").
 					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000")
 			},
@@ -408,6 +517,8 @@ func TestClient_WithTLSConfig(t *testing.T) {
 					Language:         "ENG",
 					URL:              "https://tsp.demo.sk.ee/mid-api",
 					Timeout:          60 * time.Second,
+					RequestTimeout:   30 * time.Second,
+					SessionTimeout:   2 * time.Minute,
 				},
 			},
 		},
@@ -416,7 +527,7 @@ func TestClient_WithTLSConfig(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := NewClient()
-			tt.before(c)
+			c = tt.before(c)
 
 			clientImpl := c.(*client)
 			assert.Equal(t, tt.expected.config, clientImpl.config)
@@ -424,17 +535,527 @@ func TestClient_WithTLSConfig(t *testing.T) {
 	}
 }
 
+func Test_WithTrustStore(t *testing.T) {
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, root))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		param    *TrustStore
+		expected *TrustStore
+	}{
+		{
+			name:     "Success",
+			param:    store,
+			expected: store,
+		},
+		{
+			name:     "Nil",
+			param:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithTrustStore(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.trustStore)
+		})
+	}
+}
+
+func Test_WithOCSPChecker(t *testing.T) {
+	checker := NewOCSPChecker()
+
+	tests := []struct {
+		name     string
+		param    *OCSPChecker
+		expected *OCSPChecker
+	}{
+		{
+			name:     "Success",
+			param:    checker,
+			expected: checker,
+		},
+		{
+			name:     "Nil",
+			param:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithOCSPChecker(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.ocspChecker)
+		})
+	}
+}
+
+func Test_WithConnectionPool(t *testing.T) {
+	type result struct {
+		maxIdleConns        int
+		maxIdleConnsPerHost int
+		maxConnsPerHost     int
+		idleConnTimeout     time.Duration
+		keepAlive           time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		before   func(c Client) Client
+		expected result
+	}{
+		{
+			name: "Success",
+			before: func(c Client) Client {
+				return c.
+					WithMaxIdleConns(100).
+					WithMaxIdleConnsPerHost(20).
+					WithMaxConnsPerHost(50).
+					WithIdleConnTimeout(30 * time.Second).
+					WithKeepAlive(15 * time.Second)
+			},
+			expected: result{
+				maxIdleConns:        100,
+				maxIdleConnsPerHost: 20,
+				maxConnsPerHost:     50,
+				idleConnTimeout:     30 * time.Second,
+				keepAlive:           15 * time.Second,
+			},
+		},
+		{
+			name:     "Default values",
+			before:   func(c Client) Client { return c },
+			expected: result{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient()
+			c = tt.before(c)
+
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected.maxIdleConns, clientImpl.config.MaxIdleConns)
+			assert.Equal(t, tt.expected.maxIdleConnsPerHost, clientImpl.config.MaxIdleConnsPerHost)
+			assert.Equal(t, tt.expected.maxConnsPerHost, clientImpl.config.MaxConnsPerHost)
+			assert.Equal(t, tt.expected.idleConnTimeout, clientImpl.config.IdleConnTimeout)
+			assert.Equal(t, tt.expected.keepAlive, clientImpl.config.KeepAlive)
+		})
+	}
+}
+
+func Test_SharedHTTPClient(t *testing.T) {
+	var connections atomic.Int32
+
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"state":"RUNNING"}`))
+	}))
+	testServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	testServer.Start()
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL)
+
+	clientImpl := c.(*client)
+	httpClient := clientImpl.http()
+
+	session := &Session{Id: "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6", Hash: "hash"}
+	for i := 0; i < 5; i++ {
+		_, err := c.FetchSessionFor(context.Background(), session)
+		assert.ErrorIs(t, err, errors.ErrAuthenticationIsRunning)
+	}
+
+	assert.Same(t, httpClient, clientImpl.http())
+	assert.Equal(t, int32(1), connections.Load())
+
+	derived := c.WithText("Enter PIN2").(*client)
+	assert.Same(t, httpClient, derived.http())
+
+	rebuilt := c.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}).(*client).http()
+	assert.NotSame(t, httpClient, rebuilt)
+	assert.Same(t, httpClient, clientImpl.http())
+}
+
+func Test_Validate_Aggregated(t *testing.T) {
+	c := NewClient().
+		WithRelyingPartyUUID("00000000-0000-0000-0000").
+		WithURL("://invalid").
+		WithFailoverURLs("https://mid.sk.ee/mid-api", "mid.sk.ee").
+		WithLanguage("FIN").
+		WithTimeout(0).
+		WithKeepAlive(-time.Second)
+
+	err := c.Validate()
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	fields := make([]string, 0, len(validationErr.Errors))
+	for _, fieldErr := range validationErr.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	assert.Equal(t, []string{
+		"RelyingPartyName",
+		"RelyingPartyUUID",
+		"Language",
+		"URL",
+		"FailoverURLs[1]",
+		"Timeout",
+		"KeepAlive",
+	}, fields)
+
+	assert.Equal(t, errors.ErrMissingRelyingPartyName, validationErr.Field("RelyingPartyName"))
+	assert.Equal(t, errors.ErrInvalidRelyingPartyUUID, validationErr.Field("RelyingPartyUUID"))
+	assert.Equal(t, errors.ErrInvalidURL, validationErr.Field("FailoverURLs[1]"))
+	assert.Nil(t, validationErr.Field("FailoverURLs[0]"))
+	assert.Nil(t, validationErr.Field("HashType"))
+
+	assert.ErrorIs(t, err, errors.ErrUnsupportedLanguage)
+	assert.ErrorIs(t, err, errors.ErrInvalidTimeout)
+	assert.ErrorIs(t, err, errors.ErrInvalidConnectionPool)
+	assert.NotErrorIs(t, err, errors.ErrInvalidRetryPolicy)
+}
+
+func Test_DerivedClient(t *testing.T) {
+	base := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithFailoverURLs("https://secondary.example.com")
+
+	tenant := base.
+		WithText("Sisesta PIN1").
+		WithLanguage("EST").
+		WithFailoverURLs("https://tenant.example.com").
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper { return next })
+
+	baseImpl := base.(*client)
+	tenantImpl := tenant.(*client)
+
+	assert.NotSame(t, baseImpl, tenantImpl)
+	assert.NotSame(t, baseImpl.config, tenantImpl.config)
+
+	assert.Equal(t, Text, baseImpl.config.Text)
+	assert.Equal(t, Language, baseImpl.config.Language)
+	assert.Equal(t, []string{"https://secondary.example.com"}, baseImpl.config.FailoverURLs)
+	assert.Empty(t, baseImpl.config.Middlewares)
+
+	assert.Equal(t, "Sisesta PIN1", tenantImpl.config.Text)
+	assert.Equal(t, "EST", tenantImpl.config.Language)
+	assert.Equal(t, "DEMO", tenantImpl.config.RelyingPartyName)
+	assert.Equal(t, []string{"https://tenant.example.com"}, tenantImpl.config.FailoverURLs)
+	assert.Len(t, tenantImpl.config.Middlewares, 1)
+
+	assert.Same(t, baseImpl.sessions, tenantImpl.sessions)
+	assert.Equal(t, baseImpl.config.EndpointHealth, tenantImpl.config.EndpointHealth)
+}
+
+func Test_DerivedClient_Concurrent(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"sessionID": "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6"}`))
+	}))
+	defer testServer.Close()
+
+	base := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			_, err := base.CreateSession(context.Background(), "+37269930366", "51307149560")
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+			tenant := base.WithText(fmt.Sprintf("Tenant %d", i))
+			assert.Equal(t, fmt.Sprintf("Tenant %d", i), tenant.(*client).config.Text)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, Text, base.(*client).config.Text)
+}
+
+func Test_WithHTTPClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+
+	tests := []struct {
+		name     string
+		param    *http.Client
+		expected *http.Client
+	}{
+		{
+			name:     "Success",
+			param:    httpClient,
+			expected: httpClient,
+		},
+		{
+			name:     "Nil",
+			param:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithHTTPClient(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.config.HTTPClient)
+		})
+	}
+}
+
+func Test_WithTransport(t *testing.T) {
+	transport := &http.Transport{}
+
+	tests := []struct {
+		name     string
+		param    http.RoundTripper
+		expected http.RoundTripper
+	}{
+		{
+			name:     "Success",
+			param:    transport,
+			expected: transport,
+		},
+		{
+			name:     "Nil",
+			param:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithTransport(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.config.Transport)
+		})
+	}
+}
+
+func Test_WithMiddleware(t *testing.T) {
+	var requestIds []string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIds = append(requestIds, r.Header.Get("X-Request-Id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"state":"RUNNING"}`))
+	}))
+	defer testServer.Close()
+
+	requestId := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Request-Id", "request-1")
+			return next.RoundTrip(req)
+		})
+	}
+	override := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Request-Id", req.Header.Get("X-Request-Id")+"-2")
+			return next.RoundTrip(req)
+		})
+	}
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithMiddleware(requestId, nil).
+		WithMiddleware(override)
+
+	clientImpl := c.(*client)
+	assert.Len(t, clientImpl.config.Middlewares, 2)
+
+	_, err := c.FetchSessionFor(context.Background(), &Session{Id: "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6", Hash: "hash"})
+	assert.ErrorIs(t, err, errors.ErrAuthenticationIsRunning)
+	assert.Equal(t, []string{"request-1-2"}, requestIds)
+}
+
+func Test_WithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    RetryPolicy
+		expected RetryPolicy
+	}{
+		{
+			name: "Success",
+			param: RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: 200 * time.Millisecond,
+				MaxBackoff:     5 * time.Second,
+				Multiplier:     2,
+				Jitter:         0.2,
+			},
+			expected: RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: 200 * time.Millisecond,
+				MaxBackoff:     5 * time.Second,
+				Multiplier:     2,
+				Jitter:         0.2,
+			},
+		},
+		{
+			name:     "Default values",
+			param:    RetryPolicy{},
+			expected: RetryPolicy{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithRetryPolicy(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.config.RetryPolicy)
+		})
+	}
+}
+
+func Test_WithLogger(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name     string
+		param    *slog.Logger
+		expected *slog.Logger
+	}{
+		{
+			name:     "Success",
+			param:    logger,
+			expected: logger,
+		},
+		{
+			name:     "Nil",
+			param:    nil,
+			expected: discardLogger,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithLogger(tt.param)
+			clientImpl := c.(*client)
+			assert.Same(t, tt.expected, clientImpl.logger)
+		})
+	}
+}
+
+func Test_WithLogPII(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    bool
+		expected bool
+	}{
+		{
+			name:     "Enabled",
+			param:    true,
+			expected: true,
+		},
+		{
+			name:     "Disabled",
+			param:    false,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithLogPII(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.logPII)
+		})
+	}
+}
+
+func Test_WithRedactionKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    []byte
+		expected []byte
+	}{
+		{
+			name:     "Success",
+			param:    []byte("secret"),
+			expected: []byte("secret"),
+		},
+		{
+			name:     "Empty",
+			param:    nil,
+			expected: redactionKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithRedactionKey(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.redactionKey)
+		})
+	}
+}
+
+func Test_WithCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker()
+
+	tests := []struct {
+		name     string
+		param    *CircuitBreaker
+		expected config.CircuitBreaker
+	}{
+		{
+			name:     "Success",
+			param:    breaker,
+			expected: breaker,
+		},
+		{
+			name:     "Nil",
+			param:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient().WithCircuitBreaker(tt.param)
+			clientImpl := c.(*client)
+			assert.Equal(t, tt.expected, clientImpl.config.CircuitBreaker)
+		})
+	}
+}
+
 func Test_Validate(t *testing.T) {
 	tests := []struct {
 		name     string
-		before   func(c Client)
+		before   func(c Client) Client
 		expected error
 		error    bool
 	}{
 		{
 			name: "Success",
-			before: func(c Client) {
-				c.
+			before: func(c Client) Client {
+				return c.
 					WithRelyingPartyName("DEMO").
 					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000")
 			},
@@ -443,8 +1064,8 @@ func Test_Validate(t *testing.T) {
 		},
 		{
 			name: "Error: Missing Relying Party Name",
-			before: func(c Client) {
-				c.
+			before: func(c Client) Client {
+				return c.
 					WithRelyingPartyName("").
 					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000")
 			},
@@ -453,26 +1074,226 @@ func Test_Validate(t *testing.T) {
 		},
 		{
 			name: "Error: Missing Relying Party UUID",
-			before: func(c Client) {
-				c.
+			before: func(c Client) Client {
+				return c.
 					WithRelyingPartyName("DEMO").
 					WithRelyingPartyUUID("")
 			},
 			expected: errors.ErrMissingRelyingPartyUUID,
 			error:    true,
 		},
+		{
+			name: "Error: Timeout less than 1s",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithTimeout(500 * time.Millisecond)
+			},
+			expected: errors.ErrInvalidTimeout,
+			error:    true,
+		},
+		{
+			name: "Error: Timeout greater than 120s",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithTimeout(360 * time.Second)
+			},
+			expected: errors.ErrInvalidTimeout,
+			error:    true,
+		},
+		{
+			name: "Error: Invalid request timeout",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithRequestTimeout(0)
+			},
+			expected: errors.ErrInvalidRequestTimeout,
+			error:    true,
+		},
+		{
+			name: "Error: Invalid session timeout",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithSessionTimeout(-time.Second)
+			},
+			expected: errors.ErrInvalidSessionTimeout,
+			error:    true,
+		},
+		{
+			name: "Error: Session timeout exceeds session lifetime",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithSessionTimeout(SessionLifetime + time.Second)
+			},
+			expected: errors.ErrInvalidSessionTimeout,
+			error:    true,
+		},
+		{
+			name: "Error: Invalid Relying Party UUID",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("not-a-uuid")
+			},
+			expected: errors.ErrInvalidRelyingPartyUUID,
+			error:    true,
+		},
+		{
+			name: "Error: Invalid URL",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithURL("tsp.demo.sk.ee/mid-api")
+			},
+			expected: errors.ErrInvalidURL,
+			error:    true,
+		},
+		{
+			name: "Error: Invalid failover URL",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithFailoverURLs("ftp://mid.sk.ee/mid-api")
+			},
+			expected: errors.ErrInvalidURL,
+			error:    true,
+		},
+		{
+			name: "Error: Invalid connection pool",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithMaxIdleConns(-1)
+			},
+			expected: errors.ErrInvalidConnectionPool,
+			error:    true,
+		},
+		{
+			name: "Error: Unsupported hash type",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithHashType("MD5")
+			},
+			expected: errors.ErrUnsupportedHashType,
+			error:    true,
+		},
+		{
+			name: "Error: Unsupported text format",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithTextFormat("UTF-8")
+			},
+			expected: errors.ErrUnsupportedTextFormat,
+			error:    true,
+		},
+		{
+			name: "Error: Text outside GSM-7 alphabet",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithText("Sisesta PIN1 õ")
+			},
+			expected: errors.ErrTextNotGSM7,
+			error:    true,
+		},
+		{
+			name: "Error: GSM-7 text too long",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithText("Please confirm the login to the service!!")
+			},
+			expected: errors.ErrTextTooLong,
+			error:    true,
+		},
+		{
+			name: "Error: GSM-7 text with extension characters too long",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithText("Confirm the payment of 10€ [order 123]")
+			},
+			expected: errors.ErrTextTooLong,
+			error:    true,
+		},
+		{
+			name: "Error: UCS-2 text too long",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithText("Введите PIN1 для входа").
+					WithTextFormat("UCS-2")
+			},
+			expected: errors.ErrTextTooLong,
+			error:    true,
+		},
+		{
+			name: "Success: Automatic text format",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithText("Введите PIN1").
+					WithAutoTextFormat(true)
+			},
+			expected: nil,
+			error:    false,
+		},
+		{
+			name: "Error: Unsupported language",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithLanguage("FIN")
+			},
+			expected: errors.ErrUnsupportedLanguage,
+			error:    true,
+		},
+		{
+			name: "Error: Invalid retry policy",
+			before: func(c Client) Client {
+				return c.
+					WithRelyingPartyName("DEMO").
+					WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+					WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Jitter: 1.5})
+			},
+			expected: errors.ErrInvalidRetryPolicy,
+			error:    true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := NewClient()
 
-			tt.before(c)
+			c = tt.before(c)
 
 			err := c.Validate()
 
 			if tt.error {
 				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -0,0 +1,119 @@
+package mobileid
+
+import (
+	"github.com/tab/mobileid/internal/errors"
+)
+
+// Errors returned by the client, compare them with errors.Is
+var (
+	ErrMissingRelyingPartyName = errors.ErrMissingRelyingPartyName
+	ErrMissingRelyingPartyUUID = errors.ErrMissingRelyingPartyUUID
+	ErrInvalidRelyingPartyUUID = errors.ErrInvalidRelyingPartyUUID
+	ErrInvalidURL              = errors.ErrInvalidURL
+	ErrInvalidConnectionPool   = errors.ErrInvalidConnectionPool
+
+	ErrInvalidTimeout        = errors.ErrInvalidTimeout
+	ErrInvalidRequestTimeout = errors.ErrInvalidRequestTimeout
+	ErrInvalidSessionTimeout = errors.ErrInvalidSessionTimeout
+	ErrInvalidRetryPolicy    = errors.ErrInvalidRetryPolicy
+
+	ErrUnsupportedHashType = errors.ErrUnsupportedHashType
+	ErrInvalidDigest       = errors.ErrInvalidDigest
+
+	ErrUnsupportedTextFormat = errors.ErrUnsupportedTextFormat
+	ErrUnsupportedLanguage   = errors.ErrUnsupportedLanguage
+	ErrTextNotGSM7           = errors.ErrTextNotGSM7
+	ErrTextNotUCS2           = errors.ErrTextNotUCS2
+	ErrTextTooLong           = errors.ErrTextTooLong
+
+	ErrMobileIdProviderError        = errors.ErrMobileIdProviderError
+	ErrMobileIdProviderPayloadError = errors.ErrMobileIdProviderPayloadError
+	ErrMobileIdAccessForbidden      = errors.ErrMobileIdAccessForbidden
+	ErrMobileIdMethodNotAllowed     = errors.ErrMobileIdMethodNotAllowed
+	ErrMobileIdSessionNotFound      = errors.ErrMobileIdSessionNotFound
+	ErrInvalidSessionURL            = errors.ErrInvalidSessionURL
+
+	ErrCircuitOpen = errors.ErrCircuitOpen
+
+	ErrMobileIdCertificateNotFound  = errors.ErrMobileIdCertificateNotFound
+	ErrMobileIdCertificateNotActive = errors.ErrMobileIdCertificateNotActive
+
+	ErrInvalidCertificate     = errors.ErrInvalidCertificate
+	ErrInvalidIdentityNumber  = errors.ErrInvalidIdentityNumber
+	ErrIdentityNumberMismatch = errors.ErrIdentityNumberMismatch
+	ErrMissingIdentityNumber  = errors.ErrMissingIdentityNumber
+
+	ErrFailedToGenerateRandomBytes = errors.ErrFailedToGenerateRandomBytes
+
+	ErrUnsupportedState  = errors.ErrUnsupportedState
+	ErrUnsupportedResult = errors.ErrUnsupportedResult
+
+	ErrNotMidClient          = errors.ErrNotMidClient
+	ErrUserCancelled         = errors.ErrUserCancelled
+	ErrSignatureHashMismatch = errors.ErrSignatureHashMismatch
+	ErrPhoneAbsent           = errors.ErrPhoneAbsent
+	ErrDeliveryError         = errors.ErrDeliveryError
+	ErrSimError              = errors.ErrSimError
+	ErrTimeout               = errors.ErrTimeout
+
+	ErrAuthenticationIsRunning = errors.ErrAuthenticationIsRunning
+	ErrSignatureIsRunning      = errors.ErrSignatureIsRunning
+	ErrWorkerStopped           = errors.ErrWorkerStopped
+
+	ErrMissingSessionHash            = errors.ErrMissingSessionHash
+	ErrInvalidSignature              = errors.ErrInvalidSignature
+	ErrMissingSignerCertificate      = errors.ErrMissingSignerCertificate
+	ErrSignerCertificateMismatch     = errors.ErrSignerCertificateMismatch
+	ErrUnsupportedSignatureAlgorithm = errors.ErrUnsupportedSignatureAlgorithm
+
+	ErrFailedToDecodeCertificate = errors.ErrFailedToDecodeCertificate
+	ErrFailedToParseCertificate  = errors.ErrFailedToParseCertificate
+
+	ErrFailedToReadCertificateFile   = errors.ErrFailedToReadCertificateFile
+	ErrFailedToDecodeCertificateFile = errors.ErrFailedToDecodeCertificateFile
+	ErrFailedToParseCertificateFile  = errors.ErrFailedToParseCertificateFile
+
+	ErrFailedToVerifyCertificate = errors.ErrFailedToVerifyCertificate
+
+	ErrUnsupportedTrustStoreEnvironment = errors.ErrUnsupportedTrustStoreEnvironment
+	ErrInvalidTrustStoreCertificate     = errors.ErrInvalidTrustStoreCertificate
+	ErrEmptyTrustStore                  = errors.ErrEmptyTrustStore
+	ErrCertificateExpired               = errors.ErrCertificateExpired
+	ErrInvalidCertificateKeyUsage       = errors.ErrInvalidCertificateKeyUsage
+	ErrCertificateNotTrusted            = errors.ErrCertificateNotTrusted
+
+	ErrOCSPIssuerNotFound       = errors.ErrOCSPIssuerNotFound
+	ErrOCSPResponderNotFound    = errors.ErrOCSPResponderNotFound
+	ErrOCSPCheckFailed          = errors.ErrOCSPCheckFailed
+	ErrCertificateRevoked       = errors.ErrCertificateRevoked
+	ErrCertificateStatusUnknown = errors.ErrCertificateStatusUnknown
+
+	ErrAuditLogGap       = errors.ErrAuditLogGap
+	ErrAuditLogTampered  = errors.ErrAuditLogTampered
+	ErrAuditLogInvalid   = errors.ErrAuditLogInvalid
+	ErrAuditLogTruncated = errors.ErrAuditLogTruncated
+	ErrAuditLogTornTail  = errors.ErrAuditLogTornTail
+	ErrAuditLogKey       = errors.ErrAuditLogKey
+)
+
+// ProviderError represents an error response of the Mobile-ID provider with the status code,
+// endpoint, message, time and traceId, it matches the mapped error with errors.Is
+type ProviderError = errors.ProviderError
+
+// ValidationError represents all invalid fields of the client configuration returned by Validate,
+// the error of the field is returned by Field, e.g. Field("URL")
+type ValidationError = errors.ValidationError
+
+// FieldError represents the invalid value of the config field
+type FieldError = errors.FieldError
+
+// resultErrors maps the Mobile-ID result codes to the errors
+var resultErrors = map[string]error{
+	NOT_MID_CLIENT:          ErrNotMidClient,
+	USER_CANCELLED:          ErrUserCancelled,
+	SIGNATURE_HASH_MISMATCH: ErrSignatureHashMismatch,
+	PHONE_ABSENT:            ErrPhoneAbsent,
+	DELIVERY_ERROR:          ErrDeliveryError,
+	SIM_ERROR:               ErrSimError,
+	TIMEOUT:                 ErrTimeout,
+}
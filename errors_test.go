@@ -0,0 +1,124 @@
+package mobileid
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Error_Is(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{
+			name:     "NOT_MID_CLIENT",
+			err:      &Error{Code: NOT_MID_CLIENT},
+			target:   ErrNotMidClient,
+			expected: true,
+		},
+		{
+			name:     "USER_CANCELLED",
+			err:      &Error{Code: USER_CANCELLED},
+			target:   ErrUserCancelled,
+			expected: true,
+		},
+		{
+			name:     "SIGNATURE_HASH_MISMATCH",
+			err:      &Error{Code: SIGNATURE_HASH_MISMATCH},
+			target:   ErrSignatureHashMismatch,
+			expected: true,
+		},
+		{
+			name:     "PHONE_ABSENT",
+			err:      &Error{Code: PHONE_ABSENT},
+			target:   ErrPhoneAbsent,
+			expected: true,
+		},
+		{
+			name:     "DELIVERY_ERROR",
+			err:      &Error{Code: DELIVERY_ERROR},
+			target:   ErrDeliveryError,
+			expected: true,
+		},
+		{
+			name:     "SIM_ERROR",
+			err:      &Error{Code: SIM_ERROR},
+			target:   ErrSimError,
+			expected: true,
+		},
+		{
+			name:     "TIMEOUT",
+			err:      &Error{Code: TIMEOUT},
+			target:   ErrTimeout,
+			expected: true,
+		},
+		{
+			name:     "Wrapped SignatureError",
+			err:      fmt.Errorf("wrapped: %w", &SignatureError{Code: USER_CANCELLED}),
+			target:   ErrUserCancelled,
+			expected: true,
+		},
+		{
+			name:     "Different result code",
+			err:      &Error{Code: TIMEOUT},
+			target:   ErrUserCancelled,
+			expected: false,
+		},
+		{
+			name:     "Unknown result code",
+			err:      &Error{Code: "UNKNOWN"},
+			target:   ErrUnsupportedResult,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, errors.Is(tt.err, tt.target))
+		})
+	}
+}
+
+func Test_ProviderError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ProviderError{
+		StatusCode: 400,
+		Endpoint:   "https://tsp.demo.sk.ee/mid-api/authentication",
+		Message:    "phoneNumber must contain of + and numbers(8-30)",
+		Time:       "2025-02-23T17:31:23",
+		TraceId:    "d2206fd3aedc3aee",
+		Err:        ErrMobileIdProviderPayloadError,
+	})
+
+	var providerErr *ProviderError
+	assert.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, "d2206fd3aedc3aee", providerErr.TraceId)
+	assert.ErrorIs(t, err, ErrMobileIdProviderPayloadError)
+	assert.EqualError(t, providerErr, "Mobile-ID request payload is invalid (status: 400, endpoint: https://tsp.demo.sk.ee/mid-api/authentication, message: phoneNumber must contain of + and numbers(8-30), time: 2025-02-23T17:31:23, traceId: d2206fd3aedc3aee)")
+}
+
+func Test_ValidationError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ValidationError{
+		Errors: []*FieldError{
+			{Field: "RelyingPartyName", Err: ErrMissingRelyingPartyName},
+			{Field: "URL", Err: ErrInvalidURL},
+		},
+	})
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.ErrorIs(t, err, ErrMissingRelyingPartyName)
+	assert.ErrorIs(t, err, ErrInvalidURL)
+	assert.Equal(t, ErrInvalidURL, validationErr.Field("URL"))
+	assert.Nil(t, validationErr.Field("Language"))
+
+	var fieldErr *FieldError
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "RelyingPartyName", fieldErr.Field)
+
+	assert.EqualError(t, validationErr, "RelyingPartyName: missing required configuration: RelyingPartyName; URL: invalid configuration: URL must be an absolute http or https URL")
+}
@@ -48,9 +48,18 @@ func Example_FetchSession() {
 
 	ctx := context.Background()
 
-	sessionId := "d3b8f7c3-7e0c-4a4e-9e6b-4b0e6b8e4f4c"
+	phoneNumber := "+37269930366"
+	identity := "51307149560"
+
+	session, err := client.CreateSession(ctx, phoneNumber, identity)
+	if err != nil {
+		fmt.Println("Error creating session:", err)
+		return
+	}
 
-	person, err := client.FetchSession(ctx, sessionId)
+	// FetchSession polls the session created by the same client instance,
+	// pass the persisted session to FetchSessionFor when it is polled by another instance
+	person, err := client.FetchSession(ctx, session.Id)
 	if err != nil {
 		fmt.Println("Error fetching session:", err)
 		return
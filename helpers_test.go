@@ -0,0 +1,163 @@
+package mobileid
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCA creates a root CA when parent is nil, otherwise an intermediate CA issued by parent
+func newTestCA(t *testing.T, parent *testCA, commonName string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName, Country: []string{"EE"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// writeTestCAs stores the CA certificates as PEM files in the environment subdirectory of the temporary directory
+func writeTestCAs(t *testing.T, environment string, cas ...*testCA) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, environment), 0700))
+
+	for i, ca := range cas {
+		data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+		err := os.WriteFile(filepath.Join(dir, environment, fmt.Sprintf("ca_%d.pem", i)), data, 0600)
+		assert.NoError(t, err)
+	}
+
+	return dir
+}
+
+type testIdentity struct {
+	ca          *testCA
+	key         *ecdsa.PrivateKey
+	cert        string
+	certificate *x509.Certificate
+}
+
+// newTestIdentity creates a Mobile-ID like authentication certificate issued by its own test root CA
+func newTestIdentity(t *testing.T, serialNumber, commonName string) *testIdentity {
+	t.Helper()
+
+	ca := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	return issueTestIdentity(t, ca, serialNumber, commonName, func(*x509.Certificate) {})
+}
+
+// newTestSigningIdentity creates a Mobile-ID like signing certificate issued by its own test root CA
+func newTestSigningIdentity(t *testing.T, serialNumber, commonName string) *testIdentity {
+	t.Helper()
+
+	ca := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	return issueTestIdentity(t, ca, serialNumber, commonName, func(c *x509.Certificate) {
+		c.KeyUsage = x509.KeyUsageContentCommitment
+	})
+}
+
+// issueTestIdentity creates a Mobile-ID like authentication certificate issued by the given CA
+func issueTestIdentity(t *testing.T, ca *testCA, serialNumber, commonName string, modify func(*x509.Certificate)) *testIdentity {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			SerialNumber: serialNumber,
+			Country:      []string{"EE"},
+		},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	}
+	modify(template)
+
+	issuer, signer := template, key
+	if ca != nil {
+		issuer, signer = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &testIdentity{
+		ca:          ca,
+		key:         key,
+		cert:        base64.StdEncoding.EncodeToString(der),
+		certificate: cert,
+	}
+}
+
+// trustStore returns the demo trust store with the CA certificate which issued the identity
+func (i *testIdentity) trustStore(t *testing.T) *TrustStore {
+	t.Helper()
+
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, i.ca))
+	assert.NoError(t, err)
+
+	return store
+}
+
+// response returns the COMPLETE / OK session response with the signature of the given hash
+func (i *testIdentity) response(t *testing.T, hash string) []byte {
+	t.Helper()
+
+	digest, err := base64.StdEncoding.DecodeString(hash)
+	assert.NoError(t, err)
+
+	r, s, err := ecdsa.Sign(rand.Reader, i.key, digest)
+	assert.NoError(t, err)
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return []byte(fmt.Sprintf(`{"state": "COMPLETE", "result": "OK", "signature": {"value": "%s", "algorithm": "SHA512WithECEncryption"}, "cert": "%s"}`,
+		base64.StdEncoding.EncodeToString(signature), i.cert))
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"crypto/tls"
+	"net/http"
 	"time"
 )
 
@@ -12,8 +13,56 @@ type Config struct {
 	HashType         string
 	Text             string
 	TextFormat       string
+	AutoTextFormat   bool
 	Language         string
 	URL              string
+	FailoverURLs     []string
 	Timeout          time.Duration
+	RequestTimeout   time.Duration
+	SessionTimeout   time.Duration
 	TLSConfig        *tls.Config
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	KeepAlive           time.Duration
+
+	HTTPClient  *http.Client
+	Transport   http.RoundTripper
+	Middlewares []Middleware
+
+	RetryPolicy    RetryPolicy
+	CircuitBreaker CircuitBreaker
+	EndpointHealth EndpointHealth
+}
+
+// Middleware wraps the RoundTripper of the HTTP client
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// CircuitBreaker is an interface guards the requests to the endpoints of the Mobile-ID API
+type CircuitBreaker interface {
+	Allow(url string) error
+	Record(url string, success bool)
+	Release(url string)
+}
+
+// RetryPolicy is a struct holds the retry options of the transient failures
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one, zero or one disables retries
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper limit of the delay between retries
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows with after each retry
+	Multiplier float64
+	// Jitter is the fraction of the delay randomized to spread retries, between 0 and 1
+	Jitter float64
+}
+
+// EndpointHealth is an interface tracks the health of the Mobile-ID API endpoints
+type EndpointHealth interface {
+	Healthy(url string) bool
+	Record(url string, success bool)
 }
@@ -2,29 +2,70 @@ package errors
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
 	ErrMissingRelyingPartyName = errors.New("missing required configuration: RelyingPartyName")
 	ErrMissingRelyingPartyUUID = errors.New("missing required configuration: RelyingPartyUUID")
+	ErrInvalidRelyingPartyUUID = errors.New("invalid configuration: RelyingPartyUUID must be a UUID")
+	ErrInvalidURL              = errors.New("invalid configuration: URL must be an absolute http or https URL")
+	ErrInvalidConnectionPool   = errors.New("invalid configuration: connection pool values must not be negative")
+
+	ErrInvalidTimeout        = errors.New("invalid configuration: Timeout must be between 1s and 120s")
+	ErrInvalidRequestTimeout = errors.New("invalid configuration: RequestTimeout must be greater than zero")
+	ErrInvalidSessionTimeout = errors.New("invalid configuration: SessionTimeout must be greater than zero and not exceed the 5m session lifetime")
+	ErrInvalidRetryPolicy    = errors.New("invalid configuration: RetryPolicy values must not be negative and Jitter must be between 0 and 1")
 
 	ErrUnsupportedHashType = errors.New("unsupported hash type, allowed hash types are SHA256, SHA384 or SHA512")
+	ErrInvalidDigest       = errors.New("digest length does not match the hash type")
+
+	ErrUnsupportedTextFormat = errors.New("unsupported text format, allowed text formats are GSM-7 or UCS-2")
+	ErrUnsupportedLanguage   = errors.New("unsupported language, allowed languages are EST, ENG, RUS or LIT")
+	ErrTextNotGSM7           = errors.New("invalid configuration: Text contains characters outside the GSM-7 alphabet, use UCS-2 text format")
+	ErrTextNotUCS2           = errors.New("invalid configuration: Text contains characters outside the UCS-2 alphabet")
+	ErrTextTooLong           = errors.New("invalid configuration: Text must not exceed 40 GSM-7 or 20 UCS-2 characters")
 
 	ErrMobileIdProviderError        = errors.New("Mobile-ID provider error")
 	ErrMobileIdProviderPayloadError = errors.New("Mobile-ID request payload is invalid")
 	ErrMobileIdAccessForbidden      = errors.New("Mobile-ID access forbidden. User authorization by RelyingPartyName, RelyingPartyUUID and IP-address fails")
 	ErrMobileIdMethodNotAllowed     = errors.New("Mobile-ID method not allowed. Only HTTP methods POST and OPTIONS are allowed")
 	ErrMobileIdSessionNotFound      = errors.New("Mobile-ID session not found or expired")
+	ErrInvalidSessionURL            = errors.New("session URL is not one of the configured Mobile-ID endpoints")
+
+	ErrCircuitOpen = errors.New("Mobile-ID circuit breaker is open, request is rejected")
 
-	ErrInvalidCertificate    = errors.New("invalid certificate")
-	ErrInvalidIdentityNumber = errors.New("invalid identity number")
+	ErrMobileIdCertificateNotFound  = errors.New("Mobile-ID certificate not found, person is not a Mobile-ID client")
+	ErrMobileIdCertificateNotActive = errors.New("Mobile-ID certificate is not active")
+
+	ErrInvalidCertificate     = errors.New("invalid certificate")
+	ErrInvalidIdentityNumber  = errors.New("invalid identity number")
+	ErrIdentityNumberMismatch = errors.New("certificate identity number does not match the session national identity number")
+	ErrMissingIdentityNumber  = errors.New("session national identity number not found, the certificate identity can not be verified")
 
 	ErrFailedToGenerateRandomBytes = errors.New("failed to generate random bytes")
 
 	ErrUnsupportedState  = errors.New("unsupported state, allowed states are COMPLETE or RUNNING")
 	ErrUnsupportedResult = errors.New("unsupported result, allowed results are OK or NOT_MID_CLIENT, USER_CANCELLED, SIGNATURE_HASH_MISMATCH, PHONE_ABSENT, DELIVERY_ERROR, SIM_ERROR, TIMEOUT")
 
+	ErrNotMidClient          = errors.New("person is not a Mobile-ID client or the certificates are not active")
+	ErrUserCancelled         = errors.New("user cancelled the operation")
+	ErrSignatureHashMismatch = errors.New("Mobile-ID configuration on the SIM card does not match the hash")
+	ErrPhoneAbsent           = errors.New("phone is unreachable")
+	ErrDeliveryError         = errors.New("failed to deliver the request to the phone")
+	ErrSimError              = errors.New("invalid response from the SIM card")
+	ErrTimeout               = errors.New("user did not respond in time")
+
 	ErrAuthenticationIsRunning = errors.New("authentication is still running")
+	ErrSignatureIsRunning      = errors.New("signature is still running")
+	ErrWorkerStopped           = errors.New("worker is stopped, session is not queued")
+
+	ErrMissingSessionHash            = errors.New("session hash not found, session must be created by the same client or passed with its hash")
+	ErrInvalidSignature              = errors.New("failed to verify signature")
+	ErrMissingSignerCertificate      = errors.New("signer certificate not found, the certificate must be passed to CreateSignatureSession or returned by the provider")
+	ErrSignerCertificateMismatch     = errors.New("signer certificate returned by the provider does not match the certificate of the session")
+	ErrUnsupportedSignatureAlgorithm = errors.New("unsupported signature algorithm")
 
 	ErrFailedToDecodeCertificate = errors.New("failed to decode certificate")
 	ErrFailedToParseCertificate  = errors.New("failed to parse certificate")
@@ -34,4 +75,109 @@ var (
 	ErrFailedToParseCertificateFile  = errors.New("failed to parse certificate file")
 
 	ErrFailedToVerifyCertificate = errors.New("failed to verify certificate pinning")
+
+	ErrUnsupportedTrustStoreEnvironment = errors.New("unsupported trust store environment, allowed environments are demo or production")
+	ErrInvalidTrustStoreCertificate     = errors.New("invalid trust store certificate, only CA certificates of the selected environment are allowed")
+	ErrEmptyTrustStore                  = errors.New("trust store does not contain any root CA certificates")
+	ErrCertificateExpired               = errors.New("certificate is expired or not yet valid")
+	ErrInvalidCertificateKeyUsage       = errors.New("certificate key usage does not allow the signature")
+	ErrCertificateNotTrusted            = errors.New("certificate is not issued by a trusted CA")
+
+	ErrOCSPIssuerNotFound       = errors.New("OCSP check requires a trust store with the certificate issuer")
+	ErrOCSPResponderNotFound    = errors.New("OCSP responder URL not found in certificate")
+	ErrOCSPCheckFailed          = errors.New("failed to check certificate revocation status")
+	ErrCertificateRevoked       = errors.New("certificate is revoked")
+	ErrCertificateStatusUnknown = errors.New("certificate revocation status is unknown")
+
+	ErrAuditLogGap       = errors.New("audit log record is missing, the sequence or the previous hash does not match")
+	ErrAuditLogTampered  = errors.New("audit log record is modified, the hash does not match the record")
+	ErrAuditLogInvalid   = errors.New("audit log record is not a valid JSON")
+	ErrAuditLogTruncated = errors.New("audit log ends before the expected head, records are removed from the end")
+	ErrAuditLogTornTail  = errors.New("audit log ends with a partly written record")
+	ErrAuditLogKey       = errors.New("audit log key is required, the hash chain can be recomputed without the key")
 )
+
+// ProviderError represents an error response of the Mobile-ID provider,
+// it matches the mapped sentinel error with errors.Is
+type ProviderError struct {
+	StatusCode int
+	Endpoint   string
+	Message    string
+	Time       string
+	TraceId    string
+	Err        error
+}
+
+// Error returns the error message
+func (e *ProviderError) Error() string {
+	message := fmt.Sprintf("%s (status: %d, endpoint: %s", e.Err, e.StatusCode, e.Endpoint)
+
+	if e.Message != "" {
+		message += fmt.Sprintf(", message: %s", e.Message)
+	}
+
+	if e.Time != "" {
+		message += fmt.Sprintf(", time: %s", e.Time)
+	}
+
+	if e.TraceId != "" {
+		message += fmt.Sprintf(", traceId: %s", e.TraceId)
+	}
+
+	return message + ")"
+}
+
+// Unwrap returns the mapped sentinel error
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError represents the invalid value of the config field
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error returns the error message
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+// Unwrap returns the sentinel error of the field
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError represents all invalid fields of the config,
+// it matches the sentinel error of every field with errors.Is
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error returns the error messages of all fields
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of all fields
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Field returns the sentinel error of the field or nil when the field is valid
+func (e *ValidationError) Field(field string) error {
+	for _, err := range e.Errors {
+		if err.Field == field {
+			return err.Err
+		}
+	}
+	return nil
+}
@@ -19,7 +19,38 @@ type AuthenticationResponse struct {
 	Cert      string    `json:"cert"`
 }
 
+type SignatureRequest struct {
+	RelyingPartyName       string `json:"relyingPartyName"`
+	RelyingPartyUUID       string `json:"relyingPartyUUID"`
+	NationalIdentityNumber string `json:"nationalIdentityNumber"`
+	PhoneNumber            string `json:"phoneNumber"`
+	Hash                   string `json:"hash"`
+	HashType               string `json:"hashType"`
+	Language               string `json:"language"`
+	DisplayText            string `json:"displayText"`
+	DisplayTextFormat      string `json:"displayTextFormat"`
+}
+
+type SignatureResponse struct {
+	State     string    `json:"state"`
+	Result    string    `json:"result"`
+	Signature Signature `json:"signature"`
+	Cert      string    `json:"cert,omitempty"`
+}
+
 type Signature struct {
 	Value     string `json:"value"`
 	Algorithm string `json:"algorithm"`
 }
+
+type CertificateRequest struct {
+	RelyingPartyName       string `json:"relyingPartyName"`
+	RelyingPartyUUID       string `json:"relyingPartyUUID"`
+	NationalIdentityNumber string `json:"nationalIdentityNumber"`
+	PhoneNumber            string `json:"phoneNumber"`
+}
+
+type CertificateResponse struct {
+	Result string `json:"result"`
+	Cert   string `json:"cert"`
+}
@@ -0,0 +1,136 @@
+package requests
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tab/mobileid/internal/config"
+	"github.com/tab/mobileid/internal/errors"
+)
+
+// EndpointCooldown is the period the failed endpoint is skipped while other endpoints are healthy
+const EndpointCooldown = 30 * time.Second
+
+// EndpointHealth tracks the failed endpoints of the Mobile-ID API
+type EndpointHealth struct {
+	mu          sync.Mutex
+	cooldown    time.Duration
+	failures    map[string]time.Time
+	currentTime func() time.Time
+}
+
+// NewEndpointHealth creates a new endpoint health tracker with the given cooldown period
+func NewEndpointHealth(cooldown time.Duration) *EndpointHealth {
+	return &EndpointHealth{
+		cooldown:    valueOrDefault(cooldown, EndpointCooldown),
+		failures:    make(map[string]time.Time),
+		currentTime: time.Now,
+	}
+}
+
+// Healthy reports whether the endpoint has not failed within the cooldown period
+func (h *EndpointHealth) Healthy(url string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	failedAt, ok := h.failures[url]
+	return !ok || h.currentTime().Sub(failedAt) >= h.cooldown
+}
+
+// Record records the result of the request sent to the endpoint
+func (h *EndpointHealth) Record(url string, success bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if success {
+		delete(h.failures, url)
+		return
+	}
+
+	h.failures[url] = h.currentTime()
+}
+
+// endpoints returns the configured endpoints, the healthy ones first in the configured order
+func endpoints(cfg *config.Config) []string {
+	urls := append([]string{cfg.URL}, cfg.FailoverURLs...)
+	if cfg.EndpointHealth == nil {
+		return urls
+	}
+
+	healthy := make([]string, 0, len(urls))
+	unhealthy := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if cfg.EndpointHealth.Healthy(url) {
+			healthy = append(healthy, url)
+		} else {
+			unhealthy = append(unhealthy, url)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// sessionURL returns the endpoint the session is polled on, the URL of the session must be one of the configured
+// endpoints, so the session passed back by the caller can not redirect the poll to another server
+func sessionURL(cfg *config.Config, url string) (string, error) {
+	if url == "" || url == cfg.URL {
+		return cfg.URL, nil
+	}
+
+	for _, failoverURL := range cfg.FailoverURLs {
+		if url == failoverURL {
+			return url, nil
+		}
+	}
+
+	return "", errors.ErrInvalidSessionURL
+}
+
+// failover sends the request to the endpoints until the failure of the endpoint is not accepted by failed,
+// the endpoints with open circuit are skipped, ErrCircuitOpen is returned when the circuits of all endpoints are open,
+// it returns the response and the base URL of the endpoint the response is received from
+func failover(
+	ctx context.Context,
+	cfg *config.Config,
+	failed func(response *resty.Response, err error) bool,
+	send func(url string) (*resty.Response, error),
+) (*resty.Response, string, error) {
+	var (
+		response *resty.Response
+		err      error
+		url      string
+	)
+
+	for _, endpoint := range endpoints(cfg) {
+		resp, e := send(endpoint)
+		if e == errors.ErrCircuitOpen {
+			continue
+		}
+
+		response, err, url = resp, e, endpoint
+		if ctx.Err() != nil {
+			return response, url, err
+		}
+
+		failure := failed(response, err)
+		if cfg.EndpointHealth != nil {
+			cfg.EndpointHealth.Record(url, !failure)
+		}
+
+		if !failure {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("mobileid.url", url))
+			return response, url, err
+		}
+	}
+
+	if url == "" {
+		return nil, "", errors.ErrCircuitOpen
+	}
+
+	return response, url, err
+}
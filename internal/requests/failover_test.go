@@ -0,0 +1,401 @@
+package requests
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/config"
+	"github.com/tab/mobileid/internal/errors"
+)
+
+func Test_EndpointHealth(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		before   func(h *EndpointHealth)
+		expected bool
+	}{
+		{
+			name:     "Healthy: No requests",
+			before:   func(h *EndpointHealth) {},
+			expected: true,
+		},
+		{
+			name: "Unhealthy: Failed within cooldown",
+			before: func(h *EndpointHealth) {
+				h.Record("https://primary", false)
+				now = now.Add(10 * time.Second)
+			},
+			expected: false,
+		},
+		{
+			name: "Healthy: Cooldown elapsed",
+			before: func(h *EndpointHealth) {
+				h.Record("https://primary", false)
+				now = now.Add(30 * time.Second)
+			},
+			expected: true,
+		},
+		{
+			name: "Healthy: Succeeded after failure",
+			before: func(h *EndpointHealth) {
+				h.Record("https://primary", false)
+				h.Record("https://primary", true)
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewEndpointHealth(30 * time.Second)
+			h.currentTime = func() time.Time { return now }
+
+			tt.before(h)
+
+			assert.Equal(t, tt.expected, h.Healthy("https://primary"))
+			assert.True(t, h.Healthy("https://secondary"))
+		})
+	}
+}
+
+func Test_Endpoints(t *testing.T) {
+	health := NewEndpointHealth(EndpointCooldown)
+	health.Record("https://primary", false)
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected []string
+	}{
+		{
+			name:     "Without failover",
+			cfg:      &config.Config{URL: "https://primary"},
+			expected: []string{"https://primary"},
+		},
+		{
+			name: "Without health tracking",
+			cfg: &config.Config{
+				URL:          "https://primary",
+				FailoverURLs: []string{"https://secondary", "https://tertiary"},
+			},
+			expected: []string{"https://primary", "https://secondary", "https://tertiary"},
+		},
+		{
+			name: "Unhealthy primary is tried last",
+			cfg: &config.Config{
+				URL:            "https://primary",
+				FailoverURLs:   []string{"https://secondary", "https://tertiary"},
+				EndpointHealth: health,
+			},
+			expected: []string{"https://secondary", "https://tertiary", "https://primary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, endpoints(tt.cfg))
+		})
+	}
+}
+
+func Test_Failover_CreateSession(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	unreachable := "http://" + listener.Addr().String()
+	listener.Close()
+
+	tests := []struct {
+		name      string
+		status    int
+		primary   func(url string) string
+		requests  int32
+		secondary int32
+		err       error
+	}{
+		{
+			name:      "Success: Primary service unavailable",
+			status:    http.StatusServiceUnavailable,
+			requests:  1,
+			secondary: 1,
+			err:       nil,
+		},
+		{
+			name:      "Success: Primary connection error",
+			primary:   func(string) string { return unreachable },
+			requests:  0,
+			secondary: 1,
+			err:       nil,
+		},
+		{
+			name:      "Error: Primary server error is not failed over",
+			status:    http.StatusInternalServerError,
+			requests:  1,
+			secondary: 0,
+			err:       errors.ErrMobileIdProviderError,
+		},
+		{
+			name:      "Error: Primary gateway timeout is not failed over",
+			status:    http.StatusGatewayTimeout,
+			requests:  1,
+			secondary: 0,
+			err:       errors.ErrMobileIdProviderError,
+		},
+		{
+			name:      "Error: Primary client error is not failed over",
+			status:    http.StatusBadRequest,
+			requests:  1,
+			secondary: 0,
+			err:       errors.ErrMobileIdProviderPayloadError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests, secondaryRequests atomic.Int32
+
+			primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer primary.Close()
+
+			secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				secondaryRequests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"sessionID": "8fdb516d-1a82-43ba-b82d-be63df569b86"}`))
+			}))
+			defer secondary.Close()
+
+			primaryURL := primary.URL
+			if tt.primary != nil {
+				primaryURL = tt.primary(primaryURL)
+			}
+
+			health := NewEndpointHealth(EndpointCooldown)
+			cfg := &config.Config{
+				RelyingPartyName: "DEMO",
+				RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+				HashType:         "SHA512",
+				URL:              primaryURL,
+				FailoverURLs:     []string{secondary.URL},
+				EndpointHealth:   health,
+			}
+
+			response, err := CreateAuthenticationSession(context.Background(), NewHTTPClient(cfg), cfg, "+37269930366", "51307149560")
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.True(t, health.Healthy(primaryURL))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, secondary.URL, response.URL)
+				assert.False(t, health.Healthy(primaryURL))
+			}
+			assert.Equal(t, tt.requests, requests.Load())
+			assert.Equal(t, tt.secondary, secondaryRequests.Load())
+		})
+	}
+}
+
+func Test_Failover_CreateSession_Delivered(t *testing.T) {
+	var primaryRequests, secondaryRequests atomic.Int32
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sessionID": "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6"}`))
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sessionID": "8fdb516d-1a82-43ba-b82d-be63df569b86"}`))
+	}))
+	defer secondary.Close()
+
+	cfg := &config.Config{
+		RelyingPartyName: "DEMO",
+		RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+		HashType:         "SHA512",
+		URL:              primary.URL,
+		FailoverURLs:     []string{secondary.URL},
+		RequestTimeout:   50 * time.Millisecond,
+		RetryPolicy:      config.RetryPolicy{MaxAttempts: 3},
+	}
+
+	response, err := CreateAuthenticationSession(context.Background(), NewHTTPClient(cfg), cfg, "+37269930366", "51307149560")
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, int32(1), primaryRequests.Load())
+	assert.Equal(t, int32(0), secondaryRequests.Load())
+}
+
+func Test_Failover_FetchCertificate(t *testing.T) {
+	var secondaryRequests atomic.Int32
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": "NOT_FOUND"}`))
+	}))
+	defer secondary.Close()
+
+	cfg := &config.Config{
+		URL:            primary.URL,
+		FailoverURLs:   []string{secondary.URL},
+		RequestTimeout: 50 * time.Millisecond,
+	}
+
+	response, err := FetchCertificate(context.Background(), NewHTTPClient(cfg), cfg, "+37269930366", "51307149560")
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT_FOUND", response.Result)
+	assert.Equal(t, int32(1), secondaryRequests.Load())
+}
+
+type openCircuits map[string]bool
+
+func (o openCircuits) Allow(url string) error {
+	if o[url] {
+		return errors.ErrCircuitOpen
+	}
+	return nil
+}
+
+func (o openCircuits) Record(string, bool) {}
+func (o openCircuits) Release(string)      {}
+
+func Test_Failover_CircuitOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		open      func(primary, secondary string) openCircuits
+		primary   int32
+		secondary int32
+		err       error
+	}{
+		{
+			name:      "Success: Primary circuit is open",
+			open:      func(primary, _ string) openCircuits { return openCircuits{primary: true} },
+			primary:   0,
+			secondary: 1,
+			err:       nil,
+		},
+		{
+			name: "Error: All circuits are open",
+			open: func(primary, secondary string) openCircuits {
+				return openCircuits{primary: true, secondary: true}
+			},
+			primary:   0,
+			secondary: 0,
+			err:       errors.ErrCircuitOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var primaryRequests, secondaryRequests atomic.Int32
+
+			primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				primaryRequests.Add(1)
+			}))
+			defer primary.Close()
+
+			secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				secondaryRequests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"sessionID": "8fdb516d-1a82-43ba-b82d-be63df569b86"}`))
+			}))
+			defer secondary.Close()
+
+			cfg := &config.Config{
+				RelyingPartyName: "DEMO",
+				RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+				HashType:         "SHA512",
+				URL:              primary.URL,
+				FailoverURLs:     []string{secondary.URL},
+				CircuitBreaker:   tt.open(primary.URL, secondary.URL),
+			}
+
+			response, err := CreateAuthenticationSession(context.Background(), NewHTTPClient(cfg), cfg, "+37269930366", "51307149560")
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, secondary.URL, response.URL)
+			}
+			assert.Equal(t, tt.primary, primaryRequests.Load())
+			assert.Equal(t, tt.secondary, secondaryRequests.Load())
+		})
+	}
+}
+
+func Test_SessionURL(t *testing.T) {
+	cfg := &config.Config{
+		URL:          "https://mid.sk.ee/mid-api",
+		FailoverURLs: []string{"https://mid2.sk.ee/mid-api"},
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+		err      error
+	}{
+		{
+			name:     "Empty",
+			url:      "",
+			expected: "https://mid.sk.ee/mid-api",
+		},
+		{
+			name:     "Primary",
+			url:      "https://mid.sk.ee/mid-api",
+			expected: "https://mid.sk.ee/mid-api",
+		},
+		{
+			name:     "Failover",
+			url:      "https://mid2.sk.ee/mid-api",
+			expected: "https://mid2.sk.ee/mid-api",
+		},
+		{
+			name: "Error: Unknown endpoint",
+			url:  "http://127.0.0.1:8080",
+			err:  errors.ErrInvalidSessionURL,
+		},
+		{
+			name: "Error: Endpoint prefix",
+			url:  "https://mid.sk.ee/mid-api/../evil",
+			err:  errors.ErrInvalidSessionURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := sessionURL(cfg, tt.url)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Empty(t, url)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, url)
+			}
+		})
+	}
+}
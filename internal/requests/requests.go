@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"time"
@@ -20,8 +21,11 @@ const (
 	MaxIdleConnections        = 10000
 	MaxIdleConnectionsPerHost = 10000
 	IdleConnTimeout           = 90 * time.Second
+	KeepAlive                 = 30 * time.Second
+	DialTimeout               = 30 * time.Second
 	TLSHandshakeTimeout       = 10 * time.Second
 	Timeout                   = 60 * time.Second
+	RequestTimeout            = 30 * time.Second
 
 	MinMobileIdTimeout = 1000
 	MaxMobileIdTimeout = 120000
@@ -30,6 +34,8 @@ const (
 type Response struct {
 	Id   string `json:"sessionID"`
 	Code string `json:"code"`
+	Hash string `json:"-"`
+	URL  string `json:"-"`
 }
 
 type Error struct {
@@ -40,6 +46,7 @@ type Error struct {
 
 func CreateAuthenticationSession(
 	ctx context.Context,
+	client *resty.Client,
 	cfg *config.Config,
 	phoneNumber string,
 	identity string,
@@ -61,11 +68,90 @@ func CreateAuthenticationSession(
 		DisplayTextFormat:      cfg.TextFormat,
 	}
 
-	endpoint := fmt.Sprintf("%s/authentication", cfg.URL)
-	response, err := httpClient(cfg).R().SetContext(ctx).SetBody(body).Post(endpoint)
+	return createSession(ctx, client, cfg, "/authentication", body, hash)
+}
+
+func FetchAuthenticationSession(
+	ctx context.Context,
+	client *resty.Client,
+	cfg *config.Config,
+	url string,
+	sessionId string,
+) (*models.AuthenticationResponse, error) {
+	url, err := sessionURL(cfg, url)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/authentication/session/%s", sessionId)
+
+	var result models.AuthenticationResponse
+	if err = fetchSession(ctx, client, cfg, url, path, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func CreateSignatureSession(
+	ctx context.Context,
+	client *resty.Client,
+	cfg *config.Config,
+	phoneNumber string,
+	identity string,
+	hash string,
+	hashType string,
+) (*Response, error) {
+	body := models.SignatureRequest{
+		RelyingPartyName:       cfg.RelyingPartyName,
+		RelyingPartyUUID:       cfg.RelyingPartyUUID,
+		PhoneNumber:            phoneNumber,
+		NationalIdentityNumber: identity,
+		Hash:                   hash,
+		HashType:               hashType,
+		Language:               cfg.Language,
+		DisplayText:            cfg.Text,
+		DisplayTextFormat:      cfg.TextFormat,
+	}
+
+	return createSession(ctx, client, cfg, "/signature", body, hash)
+}
+
+func FetchSignatureSession(
+	ctx context.Context,
+	client *resty.Client,
+	cfg *config.Config,
+	url string,
+	sessionId string,
+) (*models.SignatureResponse, error) {
+	url, err := sessionURL(cfg, url)
 	if err != nil {
 		return nil, err
 	}
+	path := fmt.Sprintf("/signature/session/%s", sessionId)
+
+	var result models.SignatureResponse
+	if err = fetchSession(ctx, client, cfg, url, path, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func createSession(
+	ctx context.Context,
+	client *resty.Client,
+	cfg *config.Config,
+	path string,
+	body interface{},
+	hash string,
+) (*Response, error) {
+	response, url, err := failover(ctx, cfg, isNotCreated, func(url string) (*resty.Response, error) {
+		return post(ctx, client, cfg, url, path, body, isNotDelivered)
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := url + path
 
 	switch response.StatusCode() {
 	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
@@ -82,71 +168,182 @@ func CreateAuthenticationSession(
 		return &Response{
 			Id:   result.Id,
 			Code: code,
+			Hash: hash,
+			URL:  url,
 		}, nil
-	case http.StatusBadRequest:
-		return nil, errors.ErrMobileIdProviderPayloadError
-	case http.StatusUnauthorized:
-		return nil, errors.ErrMobileIdAccessForbidden
-	case http.StatusMethodNotAllowed:
-		return nil, errors.ErrMobileIdMethodNotAllowed
 	default:
-		return nil, errors.ErrMobileIdProviderError
+		return nil, providerError(response, endpoint, requestError(response.StatusCode()))
 	}
 }
 
-func FetchAuthenticationSession(
+func FetchCertificate(
 	ctx context.Context,
+	client *resty.Client,
 	cfg *config.Config,
-	sessionId string,
-) (*models.AuthenticationResponse, error) {
-	endpoint := fmt.Sprintf("%s/authentication/session/%s", cfg.URL, sessionId)
-
-	timeout := int(cfg.Timeout.Milliseconds())
-
-	switch {
-	case timeout < MinMobileIdTimeout:
-		timeout = MinMobileIdTimeout
-	case timeout > MaxMobileIdTimeout:
-		timeout = MaxMobileIdTimeout
+	phoneNumber string,
+	identity string,
+) (*models.CertificateResponse, error) {
+	body := models.CertificateRequest{
+		RelyingPartyName:       cfg.RelyingPartyName,
+		RelyingPartyUUID:       cfg.RelyingPartyUUID,
+		PhoneNumber:            phoneNumber,
+		NationalIdentityNumber: identity,
 	}
 
-	response, err := httpClient(cfg).R().
-		SetContext(ctx).
-		SetQueryParam("timeoutMs", strconv.Itoa(timeout)).
-		Get(endpoint)
+	response, url, err := failover(ctx, cfg, isTransient, func(url string) (*resty.Response, error) {
+		return post(ctx, client, cfg, url, "/certificate", body, isTransient)
+	})
 	if err != nil {
 		return nil, err
 	}
+	endpoint := url + "/certificate"
 
 	switch response.StatusCode() {
 	case http.StatusOK:
-		var result models.AuthenticationResponse
+		var result models.CertificateResponse
 		if err = json.Unmarshal(response.Body(), &result); err != nil {
 			return nil, err
 		}
 		return &result, nil
+	default:
+		return nil, providerError(response, endpoint, requestError(response.StatusCode()))
+	}
+}
+
+func fetchSession(
+	ctx context.Context,
+	client *resty.Client,
+	cfg *config.Config,
+	url string,
+	path string,
+	result interface{},
+) error {
+	endpoint := url + path
+
+	response, err := retry(ctx, cfg, url, isTransient, func() (*resty.Response, error) {
+		timeout := pollTimeout(ctx, cfg)
+
+		ctx, cancel := context.WithTimeout(ctx, timeout+valueOrDefault(cfg.RequestTimeout, RequestTimeout))
+		defer cancel()
+
+		return client.R().
+			SetContext(ctx).
+			SetQueryParam("timeoutMs", strconv.FormatInt(timeout.Milliseconds(), 10)).
+			Get(endpoint)
+	})
+	if err != nil {
+		return err
+	}
+
+	switch response.StatusCode() {
+	case http.StatusOK:
+		return json.Unmarshal(response.Body(), result)
 	case http.StatusForbidden:
-		return nil, errors.ErrMobileIdAccessForbidden
+		return providerError(response, endpoint, errors.ErrMobileIdAccessForbidden)
 	case http.StatusNotFound:
-		return nil, errors.ErrMobileIdSessionNotFound
+		return providerError(response, endpoint, errors.ErrMobileIdSessionNotFound)
 	default:
-		return nil, errors.ErrMobileIdProviderError
+		return providerError(response, endpoint, errors.ErrMobileIdProviderError)
 	}
 }
 
-func httpClient(cfg *config.Config) *resty.Client {
-	transport := &http.Transport{
-		MaxIdleConns:        MaxIdleConnections,
-		MaxIdleConnsPerHost: MaxIdleConnectionsPerHost,
-		IdleConnTimeout:     IdleConnTimeout,
-		TLSHandshakeTimeout: TLSHandshakeTimeout,
-		TLSClientConfig:     cfg.TLSConfig,
-	}
+// post sends the POST request with the request timeout, the failures accepted by retryable are retried
+func post(
+	ctx context.Context,
+	client *resty.Client,
+	cfg *config.Config,
+	url string,
+	path string,
+	body interface{},
+	retryable func(response *resty.Response, err error) bool,
+) (*resty.Response, error) {
+	return retry(ctx, cfg, url, retryable, func() (*resty.Response, error) {
+		ctx, cancel := context.WithTimeout(ctx, valueOrDefault(cfg.RequestTimeout, RequestTimeout))
+		defer cancel()
 
-	client := resty.NewWithClient(&http.Client{
-		Transport: transport,
-		Timeout:   Timeout,
+		return client.R().SetContext(ctx).SetBody(body).Post(url + path)
 	})
+}
+
+// pollTimeout returns the long-poll duration of the session status request,
+// the duration is limited by the remaining context deadline and clamped to the range allowed by the Mobile-ID API
+func pollTimeout(ctx context.Context, cfg *config.Config) time.Duration {
+	timeout := cfg.Timeout
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	switch {
+	case timeout < MinMobileIdTimeout*time.Millisecond:
+		return MinMobileIdTimeout * time.Millisecond
+	case timeout > MaxMobileIdTimeout*time.Millisecond:
+		return MaxMobileIdTimeout * time.Millisecond
+	}
+
+	return timeout.Truncate(time.Millisecond)
+}
+
+// providerError decodes the error response body of the Mobile-ID provider
+func providerError(response *resty.Response, endpoint string, err error) error {
+	var body Error
+	_ = json.Unmarshal(response.Body(), &body)
+
+	return &errors.ProviderError{
+		StatusCode: response.StatusCode(),
+		Endpoint:   endpoint,
+		Message:    body.Error,
+		Time:       body.Time,
+		TraceId:    body.TraceId,
+		Err:        err,
+	}
+}
+
+// requestError maps the status code of the POST request to the error
+func requestError(statusCode int) error {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return errors.ErrMobileIdProviderPayloadError
+	case http.StatusUnauthorized:
+		return errors.ErrMobileIdAccessForbidden
+	case http.StatusMethodNotAllowed:
+		return errors.ErrMobileIdMethodNotAllowed
+	default:
+		return errors.ErrMobileIdProviderError
+	}
+}
+
+// NewHTTPClient builds the HTTP client from the config, the client should be reused for all requests
+// to keep connections and TLS sessions alive. The injected transport or the transport of the injected HTTP client
+// is used when set, otherwise the transport is built from the connection pool and TLS options.
+// The timeout of the injected HTTP client is cleared, the requests are limited by the request timeout
+// and the long-poll duration instead. Middlewares wrap the transport in the given order,
+// the first middleware is the outermost one
+func NewHTTPClient(cfg *config.Config) *resty.Client {
+	httpClient := &http.Client{}
+	if cfg.HTTPClient != nil {
+		*httpClient = *cfg.HTTPClient
+		httpClient.Timeout = 0
+	}
+
+	var transport http.RoundTripper
+	switch {
+	case cfg.Transport != nil:
+		transport = cfg.Transport
+	case httpClient.Transport != nil:
+		transport = httpClient.Transport
+	default:
+		transport = newTransport(cfg)
+	}
+
+	for i := len(cfg.Middlewares) - 1; i >= 0; i-- {
+		transport = cfg.Middlewares[i](transport)
+	}
+	httpClient.Transport = transport
+
+	client := resty.NewWithClient(httpClient)
 
 	client.
 		SetHeader("Accept", "application/json").
@@ -154,3 +351,29 @@ func httpClient(cfg *config.Config) *resty.Client {
 
 	return client
 }
+
+// newTransport builds the transport from the connection pool and TLS options of the config
+func newTransport(cfg *config.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   DialTimeout,
+			KeepAlive: valueOrDefault(cfg.KeepAlive, KeepAlive),
+		}).DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        valueOrDefault(cfg.MaxIdleConns, MaxIdleConnections),
+		MaxIdleConnsPerHost: valueOrDefault(cfg.MaxIdleConnsPerHost, MaxIdleConnectionsPerHost),
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		IdleConnTimeout:     valueOrDefault(cfg.IdleConnTimeout, IdleConnTimeout),
+		TLSHandshakeTimeout: TLSHandshakeTimeout,
+		TLSClientConfig:     cfg.TLSConfig,
+	}
+}
+
+// valueOrDefault returns the default value when the value is not set
+func valueOrDefault[T int | time.Duration](value, defaultValue T) T {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
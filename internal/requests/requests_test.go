@@ -2,8 +2,10 @@ package requests
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -152,11 +154,11 @@ func Test_CreateAuthenticationSession(t *testing.T) {
 
 			cfg.URL = testServer.URL
 
-			response, err := CreateAuthenticationSession(ctx, cfg, tt.phoneNumber, tt.identity)
+			response, err := CreateAuthenticationSession(ctx, NewHTTPClient(cfg), cfg, tt.phoneNumber, tt.identity)
 
 			if tt.err != nil {
 				assert.Error(t, err)
-				assert.Equal(t, tt.err, err)
+				assert.ErrorIs(t, err, tt.err)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expected.Id, response.Id)
@@ -243,12 +245,95 @@ func Test_FetchAuthenticationSession_Timeout(t *testing.T) {
 
 			tt.cfg.URL = testServer.URL
 
-			_, err := FetchAuthenticationSession(ctx, tt.cfg, id)
+			_, err := FetchAuthenticationSession(ctx, NewHTTPClient(tt.cfg), tt.cfg, tt.cfg.URL, id)
 			assert.NoError(t, err)
 		})
 	}
 }
 
+func Test_FetchAuthenticationSession_Deadline(t *testing.T) {
+	id := "8fdb516d-1a82-43ba-b82d-be63df569b86"
+
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		timeout  time.Duration
+		min      int64
+		max      int64
+	}{
+		{
+			name:     "Success (timeoutMs derived from remaining deadline)",
+			deadline: 5 * time.Second,
+			timeout:  60 * time.Second,
+			min:      4000,
+			max:      5000,
+		},
+		{
+			name:     "Success (timeout shorter than remaining deadline)",
+			deadline: 60 * time.Second,
+			timeout:  10 * time.Second,
+			min:      10000,
+			max:      10000,
+		},
+		{
+			name:     "Success (remaining deadline less than MinMobileIdTimeout = 1000 ms)",
+			deadline: 500 * time.Millisecond,
+			timeout:  60 * time.Second,
+			min:      1000,
+			max:      1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				timeout, err := strconv.ParseInt(r.URL.Query().Get("timeoutMs"), 10, 64)
+				assert.NoError(t, err)
+				assert.GreaterOrEqual(t, timeout, tt.min)
+				assert.LessOrEqual(t, timeout, tt.max)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"state": "COMPLETE"}`))
+			}))
+			defer testServer.Close()
+
+			cfg := &config.Config{
+				RelyingPartyName: "DEMO",
+				RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+				URL:              testServer.URL,
+				Timeout:          tt.timeout,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.deadline)
+			defer cancel()
+
+			_, err := FetchAuthenticationSession(ctx, NewHTTPClient(cfg), cfg, cfg.URL, id)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_RequestTimeout(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	cfg := &config.Config{
+		RelyingPartyName: "DEMO",
+		RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+		HashType:         "SHA512",
+		URL:              testServer.URL,
+		Timeout:          time.Second,
+		RequestTimeout:   50 * time.Millisecond,
+	}
+
+	_, err := CreateAuthenticationSession(context.Background(), NewHTTPClient(cfg), cfg, "+37269930366", "51307149560")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func Test_FetchAuthenticationSession(t *testing.T) {
 	ctx := context.Background()
 
@@ -384,11 +469,11 @@ func Test_FetchAuthenticationSession(t *testing.T) {
 
 			cfg.URL = testServer.URL
 
-			response, err := FetchAuthenticationSession(ctx, cfg, id)
+			response, err := FetchAuthenticationSession(ctx, NewHTTPClient(cfg), cfg, cfg.URL, id)
 
 			if tt.error {
 				assert.Error(t, err)
-				assert.Equal(t, tt.err, err)
+				assert.ErrorIs(t, err, tt.err)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expected.State, response.State)
@@ -397,3 +482,464 @@ func Test_FetchAuthenticationSession(t *testing.T) {
 		})
 	}
 }
+
+func Test_CreateSignatureSession(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		RelyingPartyName: "DEMO",
+		RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+		Text:             "Sign document",
+		TextFormat:       "GSM-7",
+		Language:         "ENG",
+		HashType:         "SHA512",
+		Timeout:          10 * time.Second,
+	}
+
+	hash := "kc5Rp9QxJ8b2ACXwjUl9V8BqN4v5YHRO8CQwO5zMhMU="
+
+	tests := []struct {
+		name     string
+		before   func(w http.ResponseWriter, r *http.Request)
+		expected *Response
+		err      error
+	}{
+		{
+			name: "Success",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/signature", r.URL.Path)
+
+				var body models.SignatureRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, hash, body.Hash)
+				assert.Equal(t, "SHA256", body.HashType)
+				assert.Equal(t, "Sign document", body.DisplayText)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"sessionID": "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43"}`))
+			},
+			expected: &Response{
+				Id:   "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+				Hash: hash,
+			},
+			err: nil,
+		},
+		{
+			name: "Error: Bad Request",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			err: errors.ErrMobileIdProviderPayloadError,
+		},
+		{
+			name: "Error: Unauthorized",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			err: errors.ErrMobileIdAccessForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
+			defer testServer.Close()
+
+			cfg.URL = testServer.URL
+
+			response, err := CreateSignatureSession(ctx, NewHTTPClient(cfg), cfg, "+37268000769", "60001017869", hash, "SHA256")
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.Id, response.Id)
+				assert.Equal(t, tt.expected.Hash, response.Hash)
+				assert.Len(t, response.Code, 4)
+			}
+		})
+	}
+}
+
+func Test_FetchSignatureSession(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		RelyingPartyName: "DEMO",
+		RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+		Timeout:          10 * time.Second,
+	}
+
+	id := "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43"
+
+	tests := []struct {
+		name     string
+		before   func(w http.ResponseWriter, r *http.Request)
+		expected *models.SignatureResponse
+		err      error
+	}{
+		{
+			name: "Success",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/signature/session/"+id, r.URL.Path)
+				assert.Equal(t, "10000", r.URL.Query().Get("timeoutMs"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"state": "COMPLETE", "result": "OK", "signature": {"value": "c2lnbmF0dXJl", "algorithm": "SHA256WithECEncryption"}}`))
+			},
+			expected: &models.SignatureResponse{
+				State:  "COMPLETE",
+				Result: "OK",
+				Signature: models.Signature{
+					Value:     "c2lnbmF0dXJl",
+					Algorithm: "SHA256WithECEncryption",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "Error: Not found",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+			},
+			err: errors.ErrMobileIdSessionNotFound,
+		},
+		{
+			name: "Error: InternalServerError",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			err: errors.ErrMobileIdProviderError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
+			defer testServer.Close()
+
+			cfg.URL = testServer.URL
+
+			response, err := FetchSignatureSession(ctx, NewHTTPClient(cfg), cfg, cfg.URL, id)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, response)
+			}
+		})
+	}
+}
+
+func Test_FetchCertificate(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		RelyingPartyName: "DEMO",
+		RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+	}
+
+	tests := []struct {
+		name     string
+		before   func(w http.ResponseWriter, r *http.Request)
+		expected *models.CertificateResponse
+		err      error
+	}{
+		{
+			name: "Success",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/certificate", r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"result": "NOT_FOUND"}`))
+			},
+			expected: &models.CertificateResponse{
+				Result: "NOT_FOUND",
+			},
+			err: nil,
+		},
+		{
+			name: "Error: Unauthorized",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			err: errors.ErrMobileIdAccessForbidden,
+		},
+		{
+			name: "Error: MethodNotAllowed",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			},
+			err: errors.ErrMobileIdMethodNotAllowed,
+		},
+		{
+			name: "Error: InternalServerError",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			err: errors.ErrMobileIdProviderError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
+			defer testServer.Close()
+
+			cfg.URL = testServer.URL
+
+			response, err := FetchCertificate(ctx, NewHTTPClient(cfg), cfg, "+37268000769", "60001017869")
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, response)
+			}
+		})
+	}
+}
+
+func Test_ProviderError(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		RelyingPartyName: "DEMO",
+		RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+		Text:             "Enter PIN1",
+		TextFormat:       "GSM-7",
+		Language:         "ENG",
+		HashType:         "SHA512",
+		Timeout:          10 * time.Second,
+	}
+
+	tests := []struct {
+		name     string
+		before   func(w http.ResponseWriter, r *http.Request)
+		expected *errors.ProviderError
+	}{
+		{
+			name: "Error: Bad Request",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(Error{
+					Error:   "phoneNumber must contain of + and numbers(8-30)",
+					Time:    "2025-02-23T17:31:23",
+					TraceId: "d2206fd3aedc3aee",
+				})
+			},
+			expected: &errors.ProviderError{
+				StatusCode: http.StatusBadRequest,
+				Endpoint:   "/authentication",
+				Message:    "phoneNumber must contain of + and numbers(8-30)",
+				Time:       "2025-02-23T17:31:23",
+				TraceId:    "d2206fd3aedc3aee",
+				Err:        errors.ErrMobileIdProviderPayloadError,
+			},
+		},
+		{
+			name: "Error: Internal Server Error without body",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expected: &errors.ProviderError{
+				StatusCode: http.StatusInternalServerError,
+				Endpoint:   "/authentication",
+				Err:        errors.ErrMobileIdProviderError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
+			defer testServer.Close()
+
+			cfg.URL = testServer.URL
+			tt.expected.Endpoint = testServer.URL + tt.expected.Endpoint
+
+			_, err := CreateAuthenticationSession(ctx, NewHTTPClient(cfg), cfg, "+37269930366", "51307149560")
+
+			var providerErr *errors.ProviderError
+			assert.ErrorAs(t, err, &providerErr)
+			assert.Equal(t, tt.expected, providerErr)
+			assert.ErrorIs(t, err, tt.expected.Err)
+		})
+	}
+}
+
+func Test_NewHTTPClient(t *testing.T) {
+	type result struct {
+		maxIdleConns        int
+		maxIdleConnsPerHost int
+		maxConnsPerHost     int
+		idleConnTimeout     time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected result
+	}{
+		{
+			name: "Default values",
+			cfg:  &config.Config{},
+			expected: result{
+				maxIdleConns:        MaxIdleConnections,
+				maxIdleConnsPerHost: MaxIdleConnectionsPerHost,
+				maxConnsPerHost:     0,
+				idleConnTimeout:     IdleConnTimeout,
+			},
+		},
+		{
+			name: "Custom values",
+			cfg: &config.Config{
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 20,
+				MaxConnsPerHost:     50,
+				IdleConnTimeout:     30 * time.Second,
+				KeepAlive:           15 * time.Second,
+			},
+			expected: result{
+				maxIdleConns:        100,
+				maxIdleConnsPerHost: 20,
+				maxConnsPerHost:     50,
+				idleConnTimeout:     30 * time.Second,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewHTTPClient(tt.cfg)
+
+			transport, ok := client.GetClient().Transport.(*http.Transport)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected.maxIdleConns, transport.MaxIdleConns)
+			assert.Equal(t, tt.expected.maxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+			assert.Equal(t, tt.expected.maxConnsPerHost, transport.MaxConnsPerHost)
+			assert.Equal(t, tt.expected.idleConnTimeout, transport.IdleConnTimeout)
+		})
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_NewHTTPClient_Middlewares(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state": "RUNNING"}`))
+	}))
+	defer testServer.Close()
+
+	var order []string
+	middleware := func(name string) config.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		order = append(order, "transport")
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected []string
+	}{
+		{
+			name: "Built-in transport",
+			cfg: &config.Config{
+				Middlewares: []config.Middleware{middleware("first"), middleware("second")},
+			},
+			expected: []string{"first", "second"},
+		},
+		{
+			name: "Injected transport",
+			cfg: &config.Config{
+				Transport:   transport,
+				Middlewares: []config.Middleware{middleware("first"), middleware("second")},
+			},
+			expected: []string{"first", "second", "transport"},
+		},
+		{
+			name: "Injected HTTP client",
+			cfg: &config.Config{
+				HTTPClient:  &http.Client{Transport: transport},
+				Middlewares: []config.Middleware{middleware("first")},
+			},
+			expected: []string{"first", "transport"},
+		},
+		{
+			name: "Injected transport overrides HTTP client transport",
+			cfg: &config.Config{
+				HTTPClient: &http.Client{Transport: http.DefaultTransport},
+				Transport:  transport,
+			},
+			expected: []string{"transport"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order = nil
+
+			tt.cfg.URL = testServer.URL
+			tt.cfg.Timeout = time.Second
+
+			_, err := FetchAuthenticationSession(context.Background(), NewHTTPClient(tt.cfg), tt.cfg, tt.cfg.URL, "8fdb516d-1a82-43ba-b82d-be63df569b86")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, order)
+		})
+	}
+}
+
+func Test_NewHTTPClient_InjectedClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+
+	client := NewHTTPClient(&config.Config{HTTPClient: httpClient})
+
+	assert.NotSame(t, httpClient, client.GetClient())
+	assert.Zero(t, client.GetClient().Timeout)
+	assert.Equal(t, 5*time.Second, httpClient.Timeout)
+	assert.Nil(t, httpClient.Transport)
+	assert.Nil(t, httpClient.Jar)
+
+	transport, ok := client.GetClient().Transport.(*http.Transport)
+	assert.True(t, ok)
+	assert.Equal(t, MaxIdleConnections, transport.MaxIdleConns)
+}
+
+func Test_NewHTTPClient_InjectedClientTimeout(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state": "RUNNING"}`))
+	}))
+	defer testServer.Close()
+
+	cfg := &config.Config{
+		URL:        testServer.URL,
+		Timeout:    time.Second,
+		HTTPClient: &http.Client{Timeout: 10 * time.Millisecond},
+	}
+
+	_, err := FetchAuthenticationSession(context.Background(), NewHTTPClient(cfg), cfg, cfg.URL, "8fdb516d-1a82-43ba-b82d-be63df569b86")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,145 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tab/mobileid/internal/config"
+	errs "github.com/tab/mobileid/internal/errors"
+)
+
+const (
+	RetryInitialBackoff = 200 * time.Millisecond
+	RetryMaxBackoff     = 5 * time.Second
+	RetryMultiplier     = 2.0
+)
+
+// retry sends the request to the endpoint until it succeeds, the failure is not retryable,
+// the attempts of the policy are exhausted or the context is done
+func retry(
+	ctx context.Context,
+	cfg *config.Config,
+	url string,
+	retryable func(response *resty.Response, err error) bool,
+	send func() (*resty.Response, error),
+) (*resty.Response, error) {
+	policy := cfg.RetryPolicy
+
+	span := trace.SpanFromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		response, err := guard(ctx, cfg.CircuitBreaker, url, send)
+		if err == errs.ErrCircuitOpen {
+			return nil, err
+		}
+
+		span.SetAttributes(attribute.Int("mobileid.attempts", attempt))
+		if response != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode()))
+		}
+
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !retryable(response, err) {
+			return response, err
+		}
+
+		timer := time.NewTimer(backoff(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, err
+		case <-timer.C:
+		}
+	}
+}
+
+// guard sends the request through the circuit of the endpoint, the requests cancelled by the caller are released
+// without being counted as successes or failures
+func guard(
+	ctx context.Context,
+	breaker config.CircuitBreaker,
+	url string,
+	send func() (*resty.Response, error),
+) (*resty.Response, error) {
+	if breaker == nil {
+		return send()
+	}
+
+	if err := breaker.Allow(url); err != nil {
+		return nil, err
+	}
+
+	response, err := send()
+	if ctx.Err() != nil {
+		breaker.Release(url)
+		return response, err
+	}
+
+	breaker.Record(url, !isTransient(response, err))
+
+	return response, err
+}
+
+// backoff returns the exponential delay with jitter before the next attempt
+func backoff(policy config.RetryPolicy, attempt int) time.Duration {
+	initial := valueOrDefault(policy.InitialBackoff, RetryInitialBackoff)
+	limit := valueOrDefault(policy.MaxBackoff, RetryMaxBackoff)
+
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = RetryMultiplier
+	}
+
+	delay := time.Duration(math.Min(float64(initial)*math.Pow(multiplier, float64(attempt-1)), float64(limit)))
+
+	if policy.Jitter > 0 {
+		jitter := math.Min(policy.Jitter, 1)
+		delay = time.Duration(float64(delay) * (1 - jitter + 2*jitter*rand.Float64()))
+	}
+
+	return delay
+}
+
+// isTransient reports whether the idempotent request failed with network error or server side error
+func isTransient(response *resty.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	status := response.StatusCode()
+	return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+}
+
+// isNotDelivered reports whether the request failed before it was sent to the provider
+func isNotDelivered(_ *resty.Response, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isNotCreated reports whether the session creation request was not delivered or the provider is unavailable,
+// so the session is not created and the request may be sent to another endpoint without a second prompt on the phone,
+// other server side errors, e.g. from a gateway, do not prove the session was not created and are not failed over
+func isNotCreated(response *resty.Response, err error) bool {
+	if err != nil {
+		return isNotDelivered(response, err)
+	}
+
+	return response.StatusCode() == http.StatusServiceUnavailable
+}
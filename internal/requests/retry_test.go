@@ -0,0 +1,282 @@
+package requests
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/config"
+	"github.com/tab/mobileid/internal/errors"
+)
+
+func Test_Retry_FetchSession(t *testing.T) {
+	id := "8fdb516d-1a82-43ba-b82d-be63df569b86"
+
+	tests := []struct {
+		name     string
+		failures int32
+		status   int
+		policy   config.RetryPolicy
+		attempts int32
+		err      error
+	}{
+		{
+			name:     "Success: Retried after server errors",
+			failures: 2,
+			status:   http.StatusServiceUnavailable,
+			policy:   config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			attempts: 3,
+			err:      nil,
+		},
+		{
+			name:     "Success: Retried after too many requests",
+			failures: 1,
+			status:   http.StatusTooManyRequests,
+			policy:   config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			attempts: 2,
+			err:      nil,
+		},
+		{
+			name:     "Error: Attempts exhausted",
+			failures: 5,
+			status:   http.StatusInternalServerError,
+			policy:   config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			attempts: 3,
+			err:      errors.ErrMobileIdProviderError,
+		},
+		{
+			name:     "Error: Retries disabled",
+			failures: 1,
+			status:   http.StatusInternalServerError,
+			policy:   config.RetryPolicy{},
+			attempts: 1,
+			err:      errors.ErrMobileIdProviderError,
+		},
+		{
+			name:     "Error: Not retried on client error",
+			failures: 1,
+			status:   http.StatusNotFound,
+			policy:   config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			attempts: 1,
+			err:      errors.ErrMobileIdSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"state": "RUNNING"}`))
+			}))
+			defer testServer.Close()
+
+			cfg := &config.Config{
+				RelyingPartyName: "DEMO",
+				RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+				URL:              testServer.URL,
+				Timeout:          time.Second,
+				RetryPolicy:      tt.policy,
+			}
+
+			_, err := FetchAuthenticationSession(context.Background(), NewHTTPClient(cfg), cfg, cfg.URL, id)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.attempts, attempts.Load())
+		})
+	}
+}
+
+func Test_Retry_CreateSession(t *testing.T) {
+	var attempts atomic.Int32
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer testServer.Close()
+
+	cfg := &config.Config{
+		RelyingPartyName: "DEMO",
+		RelyingPartyUUID: "00000000-0000-0000-0000-000000000000",
+		HashType:         "SHA512",
+		URL:              testServer.URL,
+		RetryPolicy:      config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}
+
+	_, err := CreateAuthenticationSession(context.Background(), NewHTTPClient(cfg), cfg, "+37269930366", "51307149560")
+	assert.ErrorIs(t, err, errors.ErrMobileIdProviderError)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+type breaker struct {
+	records  []bool
+	released int
+}
+
+func (b *breaker) Allow(string) error            { return nil }
+func (b *breaker) Record(_ string, success bool) { b.records = append(b.records, success) }
+func (b *breaker) Release(string)                { b.released++ }
+
+func Test_Guard(t *testing.T) {
+	tests := []struct {
+		name     string
+		cancel   bool
+		err      error
+		records  []bool
+		released int
+	}{
+		{
+			name:     "Success",
+			records:  []bool{true},
+			released: 0,
+		},
+		{
+			name:     "Failure",
+			err:      errors.ErrMobileIdProviderError,
+			records:  []bool{false},
+			released: 0,
+		},
+		{
+			name:     "Cancelled by caller",
+			cancel:   true,
+			err:      context.Canceled,
+			records:  nil,
+			released: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			b := &breaker{}
+			_, err := guard(ctx, b, "https://mid.sk.ee/mid-api", func() (*resty.Response, error) {
+				if tt.cancel {
+					cancel()
+				}
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+			})
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.records, b.records)
+			assert.Equal(t, tt.released, b.released)
+		})
+	}
+}
+
+func Test_Backoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  config.RetryPolicy
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:    "Default values",
+			policy:  config.RetryPolicy{},
+			attempt: 1,
+			min:     RetryInitialBackoff,
+			max:     RetryInitialBackoff,
+		},
+		{
+			name:    "Exponential growth",
+			policy:  config.RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2},
+			attempt: 3,
+			min:     400 * time.Millisecond,
+			max:     400 * time.Millisecond,
+		},
+		{
+			name:    "Limited by max backoff",
+			policy:  config.RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, Multiplier: 2},
+			attempt: 5,
+			min:     3 * time.Second,
+			max:     3 * time.Second,
+		},
+		{
+			name:    "With jitter",
+			policy:  config.RetryPolicy{InitialBackoff: time.Second, Jitter: 0.5},
+			attempt: 1,
+			min:     500 * time.Millisecond,
+			max:     1500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay := backoff(tt.policy, tt.attempt)
+
+			assert.GreaterOrEqual(t, delay, tt.min)
+			assert.LessOrEqual(t, delay, tt.max)
+		})
+	}
+}
+
+func Test_IsNotDelivered(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, dialErr := resty.New().R().Get("http://" + address)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "Connection refused",
+			err:      dialErr,
+			expected: true,
+		},
+		{
+			name:     "DNS error",
+			err:      &net.DNSError{Err: "no such host", Name: "tsp.demo.sk.ee"},
+			expected: true,
+		},
+		{
+			name:     "Read error",
+			err:      &net.OpError{Op: "read", Err: context.DeadlineExceeded},
+			expected: false,
+		},
+		{
+			name:     "Context deadline exceeded",
+			err:      context.DeadlineExceeded,
+			expected: false,
+		},
+		{
+			name:     "No error",
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isNotDelivered(nil, tt.err))
+		})
+	}
+}
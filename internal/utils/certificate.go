@@ -20,7 +20,8 @@ type Person struct {
 	LastName       string
 }
 
-func Extract(encodedCert string) (*Person, error) {
+// ParseCertificate decodes and parses the base64 encoded certificate
+func ParseCertificate(encodedCert string) (*x509.Certificate, error) {
 	certBytes, err := base64.StdEncoding.DecodeString(encodedCert)
 	if err != nil {
 		return nil, errors.ErrFailedToDecodeCertificate
@@ -31,6 +32,20 @@ func Extract(encodedCert string) (*Person, error) {
 		return nil, errors.ErrFailedToParseCertificate
 	}
 
+	return cert, nil
+}
+
+func Extract(encodedCert string) (*Person, error) {
+	cert, err := ParseCertificate(encodedCert)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExtractFromCertificate(cert)
+}
+
+// ExtractFromCertificate extracts the person details from the certificate subject
+func ExtractFromCertificate(cert *x509.Certificate) (*Person, error) {
 	parts := strings.Split(cert.Subject.CommonName, ",")
 	if len(parts) < 2 {
 		return nil, errors.ErrInvalidCertificate
@@ -53,6 +53,28 @@ func GenerateHash(hashType string) (string, error) {
 	return encodedHash, nil
 }
 
+// EncodeDigest validates the digest length for the given hash type and encodes it to base64
+func EncodeDigest(hashType string, digest []byte) (string, error) {
+	var size int
+
+	switch strings.ToUpper(hashType) {
+	case HashTypeSHA256:
+		size = sha256.Size
+	case HashTypeSHA384:
+		size = sha512.Size384
+	case HashTypeSHA512:
+		size = sha512.Size
+	default:
+		return "", errors.ErrUnsupportedHashType
+	}
+
+	if len(digest) != size {
+		return "", errors.ErrInvalidDigest
+	}
+
+	return base64.StdEncoding.EncodeToString(digest), nil
+}
+
 // GenerateVerificationCode generates a verification code based on the given hash
 func GenerateVerificationCode(hash string) (string, error) {
 	decodedHash, err := base64.StdEncoding.DecodeString(hash)
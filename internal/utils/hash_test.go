@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/errors"
 )
 
 func Test_GenerateHash(t *testing.T) {
@@ -82,3 +84,54 @@ func Test_VerificationCode(t *testing.T) {
 		})
 	}
 }
+
+func Test_EncodeDigest(t *testing.T) {
+	tests := []struct {
+		name     string
+		hashType string
+		digest   []byte
+		expected string
+		err      error
+	}{
+		{
+			name:     "Success: SHA256",
+			hashType: "SHA256",
+			digest:   make([]byte, 32),
+			expected: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+			err:      nil,
+		},
+		{
+			name:     "Success: SHA384",
+			hashType: "sha384",
+			digest:   make([]byte, 48),
+			expected: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			err:      nil,
+		},
+		{
+			name:     "Error: Digest length does not match",
+			hashType: "SHA512",
+			digest:   make([]byte, 32),
+			err:      errors.ErrInvalidDigest,
+		},
+		{
+			name:     "Error: Unsupported hash type",
+			hashType: "MD5",
+			digest:   make([]byte, 16),
+			err:      errors.ErrUnsupportedHashType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := EncodeDigest(tt.hashType, tt.digest)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Empty(t, hash)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, hash)
+			}
+		})
+	}
+}
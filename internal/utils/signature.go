@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+	"strings"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+const (
+	// AlgorithmSHA256WithEC is the ECDSA signature algorithm with SHA-256
+	AlgorithmSHA256WithEC = "SHA256WithECEncryption"
+
+	// AlgorithmSHA384WithEC is the ECDSA signature algorithm with SHA-384
+	AlgorithmSHA384WithEC = "SHA384WithECEncryption"
+
+	// AlgorithmSHA512WithEC is the ECDSA signature algorithm with SHA-512
+	AlgorithmSHA512WithEC = "SHA512WithECEncryption"
+
+	// AlgorithmSHA256WithRSA is the RSA signature algorithm with SHA-256
+	AlgorithmSHA256WithRSA = "SHA256WithRSAEncryption"
+
+	// AlgorithmSHA384WithRSA is the RSA signature algorithm with SHA-384
+	AlgorithmSHA384WithRSA = "SHA384WithRSAEncryption"
+
+	// AlgorithmSHA512WithRSA is the RSA signature algorithm with SHA-512
+	AlgorithmSHA512WithRSA = "SHA512WithRSAEncryption"
+)
+
+var algorithms = map[string]crypto.Hash{
+	strings.ToUpper(AlgorithmSHA256WithEC):  crypto.SHA256,
+	strings.ToUpper(AlgorithmSHA384WithEC):  crypto.SHA384,
+	strings.ToUpper(AlgorithmSHA512WithEC):  crypto.SHA512,
+	strings.ToUpper(AlgorithmSHA256WithRSA): crypto.SHA256,
+	strings.ToUpper(AlgorithmSHA384WithRSA): crypto.SHA384,
+	strings.ToUpper(AlgorithmSHA512WithRSA): crypto.SHA512,
+}
+
+// VerifySignature verifies the base64 encoded signature value of the base64 encoded hash
+// with the public key of the given certificate
+func VerifySignature(cert *x509.Certificate, hash, value, algorithm string) error {
+	hashFunc, ok := algorithms[strings.ToUpper(algorithm)]
+	if !ok {
+		return errors.ErrUnsupportedSignatureAlgorithm
+	}
+
+	digest, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil {
+		return errors.ErrInvalidSignature
+	}
+
+	if len(digest) != hashFunc.Size() {
+		return errors.ErrInvalidSignature
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return errors.ErrInvalidSignature
+	}
+
+	switch publicKey := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !strings.Contains(strings.ToUpper(algorithm), "WITHEC") {
+			return errors.ErrInvalidSignature
+		}
+
+		if verifyECDSA(publicKey, digest, signature) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if !strings.Contains(strings.ToUpper(algorithm), "WITHRSA") {
+			return errors.ErrInvalidSignature
+		}
+
+		if rsa.VerifyPKCS1v15(publicKey, hashFunc, digest, signature) == nil {
+			return nil
+		}
+	default:
+		return errors.ErrUnsupportedSignatureAlgorithm
+	}
+
+	return errors.ErrInvalidSignature
+}
+
+// verifyECDSA verifies the ECDSA signature, Mobile-ID returns it as concatenated r || s values,
+// ASN.1 encoded signatures are accepted as well
+func verifyECDSA(publicKey *ecdsa.PublicKey, digest, signature []byte) bool {
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+
+	if len(signature) == 2*size {
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+
+		return ecdsa.Verify(publicKey, digest, r, s)
+	}
+
+	return ecdsa.VerifyASN1(publicKey, digest, signature)
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+func Test_VerifySignature(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ecCert := &x509.Certificate{PublicKey: &ecKey.PublicKey}
+	rsaCert := &x509.Certificate{PublicKey: &rsaKey.PublicKey}
+
+	digest256 := sha256.Sum256([]byte("challenge"))
+	digest384 := sha512.Sum384([]byte("challenge"))
+	digest512 := sha512.Sum512([]byte("challenge"))
+
+	r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest384[:])
+	assert.NoError(t, err)
+	plain := make([]byte, 96)
+	r.FillBytes(plain[:48])
+	s.FillBytes(plain[48:])
+
+	asn1, err := ecdsa.SignASN1(rand.Reader, ecKey, digest512[:])
+	assert.NoError(t, err)
+
+	pkcs1, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest256[:])
+	assert.NoError(t, err)
+
+	encode := base64.StdEncoding.EncodeToString
+
+	tests := []struct {
+		name      string
+		cert      *x509.Certificate
+		hash      string
+		value     string
+		algorithm string
+		err       error
+	}{
+		{
+			name:      "Success: SHA384WithECEncryption",
+			cert:      ecCert,
+			hash:      encode(digest384[:]),
+			value:     encode(plain),
+			algorithm: "SHA384WithECEncryption",
+			err:       nil,
+		},
+		{
+			name:      "Success: SHA512WithECEncryption (ASN.1)",
+			cert:      ecCert,
+			hash:      encode(digest512[:]),
+			value:     encode(asn1),
+			algorithm: "SHA512WithECEncryption",
+			err:       nil,
+		},
+		{
+			name:      "Success: SHA256WithRSAEncryption",
+			cert:      rsaCert,
+			hash:      encode(digest256[:]),
+			value:     encode(pkcs1),
+			algorithm: "sha256WithRSAEncryption",
+			err:       nil,
+		},
+		{
+			name:      "Error: Signature of another hash",
+			cert:      ecCert,
+			hash:      encode(sha512.New384().Sum(nil)),
+			value:     encode(plain),
+			algorithm: "SHA384WithECEncryption",
+			err:       errors.ErrInvalidSignature,
+		},
+		{
+			name:      "Error: Hash length does not match algorithm",
+			cert:      rsaCert,
+			hash:      encode(digest512[:]),
+			value:     encode(pkcs1),
+			algorithm: "SHA256WithRSAEncryption",
+			err:       errors.ErrInvalidSignature,
+		},
+		{
+			name:      "Error: Algorithm does not match key type",
+			cert:      rsaCert,
+			hash:      encode(digest256[:]),
+			value:     encode(pkcs1),
+			algorithm: "SHA256WithECEncryption",
+			err:       errors.ErrInvalidSignature,
+		},
+		{
+			name:      "Error: Invalid base64 signature",
+			cert:      ecCert,
+			hash:      encode(digest384[:]),
+			value:     "invalid-signature",
+			algorithm: "SHA384WithECEncryption",
+			err:       errors.ErrInvalidSignature,
+		},
+		{
+			name:      "Error: Unsupported algorithm",
+			cert:      rsaCert,
+			hash:      encode(digest256[:]),
+			value:     encode(pkcs1),
+			algorithm: "MD5WithRSAEncryption",
+			err:       errors.ErrUnsupportedSignatureAlgorithm,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.cert, tt.hash, tt.value, tt.algorithm)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
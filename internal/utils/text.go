@@ -0,0 +1,56 @@
+package utils
+
+const (
+	// MaxGSM7TextLength is the maximum length of the display text in GSM-7 septets
+	MaxGSM7TextLength = 40
+
+	// MaxUCS2TextLength is the maximum length of the display text in UCS-2 characters
+	MaxUCS2TextLength = 20
+)
+
+const (
+	// gsm7Basic is the GSM 03.38 basic character set, the escape character is excluded
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+	// gsm7Extension is the GSM 03.38 extension table, each character is sent with the escape character
+	gsm7Extension = "\f^{}\\[~]|€"
+)
+
+var gsm7Septets = func() map[rune]int {
+	septets := make(map[rune]int)
+	for _, r := range gsm7Basic {
+		septets[r] = 1
+	}
+	for _, r := range gsm7Extension {
+		septets[r] = 2
+	}
+	return septets
+}()
+
+// GSM7Length returns the length of the text in GSM-7 septets, the extension table characters count double.
+// The result is false when the text contains a character outside the GSM-7 alphabet
+func GSM7Length(text string) (int, bool) {
+	length := 0
+	for _, r := range text {
+		septets, ok := gsm7Septets[r]
+		if !ok {
+			return 0, false
+		}
+		length += septets
+	}
+	return length, true
+}
+
+// UCS2Length returns the length of the text in UCS-2 characters,
+// the result is false when the text contains a character outside the Basic Multilingual Plane
+func UCS2Length(text string) (int, bool) {
+	length := 0
+	for _, r := range text {
+		if r > 0xFFFF {
+			return 0, false
+		}
+		length++
+	}
+	return length, true
+}
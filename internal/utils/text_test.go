@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GSM7Length(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+		ok       bool
+	}{
+		{
+			name:     "Success",
+			text:     "Enter PIN1",
+			expected: 10,
+			ok:       true,
+		},
+		{
+			name:     "Empty",
+			text:     "",
+			expected: 0,
+			ok:       true,
+		},
+		{
+			name:     "Estonian text with GSM-7 characters",
+			text:     "Sisesta PIN1 ja kinnita äö ü",
+			expected: 28,
+			ok:       true,
+		},
+		{
+			name:     "Extension table characters",
+			text:     "Pay 5€ [ref]",
+			expected: 15,
+			ok:       true,
+		},
+		{
+			name: "Estonian text with õ",
+			text: "Sisesta PIN1 ja kinnita õ",
+			ok:   false,
+		},
+		{
+			name: "Estonian text with š and ž",
+			text: "Šokolaad ja žürii",
+			ok:   false,
+		},
+		{
+			name: "Russian text",
+			text: "Введите PIN1",
+			ok:   false,
+		},
+		{
+			name: "Lithuanian text",
+			text: "Įveskite PIN1",
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, ok := GSM7Length(tt.text)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, length)
+		})
+	}
+}
+
+func Test_UCS2Length(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+		ok       bool
+	}{
+		{
+			name:     "Success",
+			text:     "Enter PIN1",
+			expected: 10,
+			ok:       true,
+		},
+		{
+			name:     "Russian text",
+			text:     "Введите PIN1",
+			expected: 12,
+			ok:       true,
+		},
+		{
+			name:     "Estonian text",
+			text:     "Sisesta PIN1 õ",
+			expected: 14,
+			ok:       true,
+		},
+		{
+			name: "Emoji",
+			text: "Enter PIN1 🔑",
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, ok := UCS2Length(tt.text)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, length)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package mobileid
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// RedactionKeySize is the size of the random key the personal data is redacted with
+const RedactionKeySize = 32
+
+// discardLogger is the default logger of the client and the worker
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// redactionKey is the random key of the process, the redacted values can not be brute-forced without it
+var redactionKey = newRedactionKey()
+
+func newRedactionKey() []byte {
+	key := make([]byte, RedactionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		panic("mobileid: failed to generate the redaction key: " + err.Error())
+	}
+
+	return key
+}
+
+// pii returns the attribute of the personal data, the value is replaced with its keyed hash
+// unless logging of personal data is enabled
+func (c *client) pii(key, value string) slog.Attr {
+	if c.logPII {
+		return slog.String(key, value)
+	}
+
+	return slog.String(key, redact(c.redactionKey, value))
+}
+
+// person returns the attributes of the authenticated person
+func (c *client) person(person *Person) slog.Attr {
+	return slog.Group("person",
+		c.pii("identity_number", person.IdentityNumber),
+		c.pii("first_name", person.FirstName),
+		c.pii("last_name", person.LastName),
+	)
+}
+
+// logError logs the failed request with the details of the provider error,
+// the requests cancelled by the caller are logged with the debug level
+func (c *client) logError(ctx context.Context, msg string, err error, attrs ...slog.Attr) {
+	level := slog.LevelWarn
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		level = slog.LevelDebug
+	}
+
+	attrs = append(attrs, slog.String("error", err.Error()))
+
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		attrs = append(attrs,
+			slog.Int("status_code", providerErr.StatusCode),
+			slog.String("endpoint", providerErr.Endpoint),
+			slog.String("trace_id", providerErr.TraceId),
+		)
+	}
+
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// redact returns the short HMAC of the value, the same values have the same hash with the same key
+// to correlate log records
+func redact(key []byte, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:6])
+}
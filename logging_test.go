@@ -0,0 +1,167 @@
+package mobileid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/models"
+)
+
+func Test_Logging(t *testing.T) {
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+
+	tests := []struct {
+		name     string
+		logPII   bool
+		expected []string
+		hidden   []string
+	}{
+		{
+			name:   "Redacted by default",
+			logPII: false,
+			expected: []string{
+				"Mobile-ID authentication session created",
+				"Mobile-ID authentication session polled",
+				"Mobile-ID authentication session completed",
+				"Mobile-ID authentication succeeded",
+				redact(redactionKey, "+37269930366"),
+				redact(redactionKey, "51307149560"),
+				redact(redactionKey, "MARY ÄNN"),
+			},
+			hidden: []string{"+37269930366", "51307149560", "MARY ÄNN", "O'CONNEŽ-ŠUSLIK"},
+		},
+		{
+			name:   "Personal data enabled",
+			logPII: true,
+			expected: []string{
+				"+37269930366",
+				"51307149560",
+				"MARY ÄNN",
+			},
+			hidden: []string{redact(redactionKey, "+37269930366")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hash string
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.Method == http.MethodPost {
+					var body models.AuthenticationRequest
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					hash = body.Hash
+
+					w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+					return
+				}
+
+				w.Write(identity.response(t, hash))
+			}))
+			defer testServer.Close()
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(identity.trustStore(t)).
+				WithLogger(logger).
+				WithLogPII(tt.logPII)
+
+			session, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+			assert.NoError(t, err)
+
+			_, err = c.FetchSession(context.Background(), session.Id)
+			assert.NoError(t, err)
+
+			for _, value := range tt.expected {
+				assert.Contains(t, buf.String(), value)
+			}
+			for _, value := range tt.hidden {
+				assert.NotContains(t, buf.String(), value)
+			}
+		})
+	}
+}
+
+func Test_Logging_ProviderError(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "phoneNumber must contain of + and numbers(8-30)", "time": "2025-02-23T17:31:23", "traceId": "d2206fd3aedc3aee"}`))
+	}))
+	defer testServer.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithLogger(logger)
+
+	_, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+	assert.ErrorIs(t, err, ErrMobileIdProviderPayloadError)
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "Mobile-ID authentication session creation failed", record["msg"])
+	assert.Equal(t, float64(http.StatusBadRequest), record["status_code"])
+	assert.Equal(t, "d2206fd3aedc3aee", record["trace_id"])
+	assert.Equal(t, redact(redactionKey, "+37269930366"), record["phone_number"])
+}
+
+func Test_Logging_Worker(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	w := NewWorker(NewClient()).WithConcurrency(2).WithLogger(logger)
+	w.Start(context.Background())
+	w.Stop()
+
+	assert.Contains(t, buf.String(), `"msg":"Mobile-ID worker started","concurrency":2,"queue_size":100`)
+	assert.Contains(t, buf.String(), `"msg":"Mobile-ID worker stopped"`)
+}
+
+func Test_Redact(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{
+			name:  "Phone number",
+			value: "+37269930366",
+		},
+		{
+			name:  "National identity number",
+			value: "51307149560",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted := redact(redactionKey, tt.value)
+
+			assert.NotContains(t, redacted, tt.value)
+			assert.Len(t, redacted, len("hmac:")+12)
+			assert.Equal(t, redacted, redact(redactionKey, tt.value))
+			assert.NotEqual(t, redacted, redact([]byte("another key"), tt.value))
+		})
+	}
+
+	assert.Equal(t, "", redact(redactionKey, ""))
+}
@@ -0,0 +1,62 @@
+package mobileid
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	FlowAuthentication = "authentication"
+	FlowSignature      = "signature"
+	FlowCertificate    = "certificate"
+)
+
+// Metrics is an interface records the metrics of the client and the worker,
+// the flow is one of FlowAuthentication, FlowSignature or FlowCertificate
+type Metrics interface {
+	// SessionCreated counts the created session
+	SessionCreated(flow string)
+	// SessionResult counts the result code of the completed session or the certificate lookup
+	SessionResult(flow, result string)
+	// ProviderError counts the error response of the Mobile-ID provider
+	ProviderError(flow string, statusCode int)
+	// CreateLatency records the duration of the session creation request
+	CreateLatency(flow string, duration time.Duration)
+	// PollLatency records the duration of the session status request
+	PollLatency(flow string, duration time.Duration)
+	// CompletionLatency records the duration from the session creation until the session is completed
+	CompletionLatency(flow string, duration time.Duration)
+	// QueueDepth records the number of the jobs waiting in the worker queue
+	QueueDepth(depth int)
+	// BusyWorkers records the number of the worker goroutines polling a session
+	BusyWorkers(busy int)
+}
+
+// noopMetrics is the default metrics of the client and the worker
+type noopMetrics struct{}
+
+func (noopMetrics) SessionCreated(string)                   {}
+func (noopMetrics) SessionResult(string, string)            {}
+func (noopMetrics) ProviderError(string, int)               {}
+func (noopMetrics) CreateLatency(string, time.Duration)     {}
+func (noopMetrics) PollLatency(string, time.Duration)       {}
+func (noopMetrics) CompletionLatency(string, time.Duration) {}
+func (noopMetrics) QueueDepth(int)                          {}
+func (noopMetrics) BusyWorkers(int)                         {}
+
+// recordProviderError counts the error when it is the error response of the Mobile-ID provider
+func (c *client) recordProviderError(flow string, err error) {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		c.metrics.ProviderError(flow, providerErr.StatusCode)
+	}
+}
+
+// recordResult counts the result code of the completed session and records its duration since creation
+func (c *client) recordResult(flow string, session *Session, result string) {
+	c.metrics.SessionResult(flow, result)
+
+	if !session.CreatedAt.IsZero() {
+		c.metrics.CompletionLatency(flow, time.Since(session.CreatedAt))
+	}
+}
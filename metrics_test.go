@@ -0,0 +1,203 @@
+package mobileid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetrics struct {
+	mu          sync.Mutex
+	created     map[string]int
+	results     map[string]int
+	errors      map[int]int
+	creates     int
+	polls       int
+	completions int
+	queueDepth  []int
+	busyWorkers []int
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{
+		created: make(map[string]int),
+		results: make(map[string]int),
+		errors:  make(map[int]int),
+	}
+}
+
+func (m *testMetrics) SessionCreated(flow string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.created[flow]++
+}
+
+func (m *testMetrics) SessionResult(flow, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[flow+":"+result]++
+}
+
+func (m *testMetrics) ProviderError(_ string, statusCode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[statusCode]++
+}
+
+func (m *testMetrics) CreateLatency(string, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.creates++
+}
+
+func (m *testMetrics) PollLatency(string, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.polls++
+}
+
+func (m *testMetrics) CompletionLatency(string, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.completions++
+}
+
+func (m *testMetrics) QueueDepth(depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queueDepth = append(m.queueDepth, depth)
+}
+
+func (m *testMetrics) BusyWorkers(busy int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.busyWorkers = append(m.busyWorkers, busy)
+}
+
+func Test_Metrics_Authenticate(t *testing.T) {
+	metrics := newTestMetrics()
+
+	testServer, store := newTestAuthenticationServer(t, 1)
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(store).
+		WithMetrics(metrics)
+
+	_, err := c.Authenticate(context.Background(), "+37269930366", "51307149560", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]int{FlowAuthentication: 1}, metrics.created)
+	assert.Equal(t, map[string]int{FlowAuthentication + ":" + OK: 1}, metrics.results)
+	assert.Empty(t, metrics.errors)
+	assert.Equal(t, 1, metrics.creates)
+	assert.Equal(t, 2, metrics.polls)
+	assert.Equal(t, 1, metrics.completions)
+}
+
+func Test_Metrics_Result(t *testing.T) {
+	metrics := newTestMetrics()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+			return
+		}
+
+		w.Write([]byte(`{"state": "COMPLETE", "result": "USER_CANCELLED"}`))
+	}))
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithMetrics(metrics)
+
+	_, err := c.Authenticate(context.Background(), "+37269930366", "51307149560", nil)
+	assert.ErrorIs(t, err, ErrUserCancelled)
+
+	assert.Equal(t, map[string]int{FlowAuthentication + ":" + USER_CANCELLED: 1}, metrics.results)
+	assert.Equal(t, 1, metrics.completions)
+}
+
+func Test_Metrics_ProviderError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expected   map[int]int
+	}{
+		{
+			name:       "Bad request",
+			statusCode: http.StatusBadRequest,
+			expected:   map[int]int{http.StatusBadRequest: 1},
+		},
+		{
+			name:       "Unauthorized",
+			statusCode: http.StatusUnauthorized,
+			expected:   map[int]int{http.StatusUnauthorized: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := newTestMetrics()
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithMetrics(metrics)
+
+			_, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+			assert.Error(t, err)
+
+			assert.Equal(t, tt.expected, metrics.errors)
+			assert.Empty(t, metrics.created)
+			assert.Equal(t, 1, metrics.creates)
+		})
+	}
+}
+
+func Test_Metrics_Worker(t *testing.T) {
+	metrics := newTestMetrics()
+
+	testServer, store := newTestAuthenticationServer(t, 2)
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(store)
+
+	w := NewWorker(c).WithConcurrency(1).WithMetrics(metrics)
+	w.Start(context.Background())
+
+	session, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+	assert.NoError(t, err)
+
+	result := <-w.Process(context.Background(), session.Id)
+	assert.NoError(t, result.Err)
+
+	w.Stop()
+
+	assert.NotEmpty(t, metrics.queueDepth)
+	assert.Equal(t, 1, metrics.busyWorkers[0])
+	assert.Equal(t, 0, metrics.busyWorkers[len(metrics.busyWorkers)-1])
+}
@@ -0,0 +1,338 @@
+package mobileid
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+const (
+	// DefaultOCSPTimeout is the default timeout of the OCSP request
+	DefaultOCSPTimeout = 5 * time.Second
+
+	// MaxOCSPResponseSize is the maximum accepted size of the OCSP response
+	MaxOCSPResponseSize = 1024 * 1024
+
+	// DefaultOCSPMaxAge is the default maximum age of the thisUpdate time of the OCSP response
+	DefaultOCSPMaxAge = time.Hour
+
+	// DefaultOCSPClockSkew is the default tolerated difference between the clocks of the responder and the client
+	DefaultOCSPClockSkew = 5 * time.Minute
+
+	// OCSPNonceSize is the size of the nonce sent in the OCSP request
+	OCSPNonceSize = 32
+)
+
+// oidOCSPNonce is the object identifier of the OCSP nonce extension
+var oidOCSPNonce = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+
+// OCSPPolicy defines how the OCSP checker handles unavailable or invalid OCSP responses
+type OCSPPolicy int
+
+const (
+	// OCSPFailClosed rejects the certificate when the revocation status can not be checked
+	OCSPFailClosed OCSPPolicy = iota
+
+	// OCSPFailOpen accepts the certificate when the revocation status can not be checked
+	OCSPFailOpen
+)
+
+type OCSPChecker struct {
+	policy      OCSPPolicy
+	timeout     time.Duration
+	maxAge      time.Duration
+	clockSkew   time.Duration
+	nonce       bool
+	responder   string
+	httpClient  *http.Client
+	mu          sync.Mutex
+	cache       map[string]ocspEntry
+	currentTime func() time.Time
+}
+
+type ocspEntry struct {
+	status     int
+	nextUpdate time.Time
+}
+
+// NewOCSPChecker creates a new OCSP checker with the fail-closed policy
+func NewOCSPChecker() *OCSPChecker {
+	return &OCSPChecker{
+		policy:      OCSPFailClosed,
+		timeout:     DefaultOCSPTimeout,
+		maxAge:      DefaultOCSPMaxAge,
+		clockSkew:   DefaultOCSPClockSkew,
+		httpClient:  &http.Client{},
+		cache:       make(map[string]ocspEntry),
+		currentTime: time.Now,
+	}
+}
+
+// WithPolicy sets the policy applied when the revocation status can not be checked
+func (o *OCSPChecker) WithPolicy(policy OCSPPolicy) *OCSPChecker {
+	o.policy = policy
+	return o
+}
+
+// WithTimeout sets the timeout of the OCSP request
+func (o *OCSPChecker) WithTimeout(timeout time.Duration) *OCSPChecker {
+	if timeout <= 0 {
+		timeout = DefaultOCSPTimeout
+	}
+
+	o.timeout = timeout
+	return o
+}
+
+// WithMaxAge sets the maximum age of the thisUpdate time of the OCSP response, older responses are rejected
+func (o *OCSPChecker) WithMaxAge(maxAge time.Duration) *OCSPChecker {
+	if maxAge <= 0 {
+		maxAge = DefaultOCSPMaxAge
+	}
+
+	o.maxAge = maxAge
+	return o
+}
+
+// WithClockSkew sets the tolerated difference between the clocks of the responder and the client
+func (o *OCSPChecker) WithClockSkew(skew time.Duration) *OCSPChecker {
+	if skew < 0 {
+		skew = DefaultOCSPClockSkew
+	}
+
+	o.clockSkew = skew
+	return o
+}
+
+// WithNonce enables the nonce in the OCSP request, the response must echo the nonce
+func (o *OCSPChecker) WithNonce(enabled bool) *OCSPChecker {
+	o.nonce = enabled
+	return o
+}
+
+// WithResponderURL overrides the OCSP responder URL from the certificate authority information access
+func (o *OCSPChecker) WithResponderURL(url string) *OCSPChecker {
+	o.responder = url
+	return o
+}
+
+// WithHTTPClient sets the HTTP client used for the OCSP requests
+func (o *OCSPChecker) WithHTTPClient(httpClient *http.Client) *OCSPChecker {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	o.httpClient = httpClient
+	return o
+}
+
+// Check checks the revocation status of the certificate issued by the issuer,
+// responses are cached until their nextUpdate time
+func (o *OCSPChecker) Check(ctx context.Context, cert, issuer *x509.Certificate) error {
+	key := ocspCacheKey(cert, issuer)
+
+	if entry, ok := o.load(key); ok {
+		return ocspStatus(entry.status)
+	}
+
+	response, err := o.fetch(ctx, cert, issuer)
+	if err != nil {
+		if o.policy == OCSPFailOpen {
+			return nil
+		}
+		return err
+	}
+
+	if !response.NextUpdate.IsZero() {
+		o.store(key, ocspEntry{status: response.Status, nextUpdate: response.NextUpdate})
+	}
+
+	return ocspStatus(response.Status)
+}
+
+func (o *OCSPChecker) fetch(ctx context.Context, cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	url := o.responder
+	if url == "" {
+		if len(cert.OCSPServer) == 0 {
+			return nil, errors.ErrOCSPResponderNotFound
+		}
+		url = cert.OCSPServer[0]
+	}
+
+	var nonce []byte
+	if o.nonce {
+		nonce = make([]byte, OCSPNonceSize)
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, errors.ErrOCSPCheckFailed
+		}
+	}
+
+	request, err := createOCSPRequest(cert, issuer, nonce)
+	if err != nil {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(request))
+	if err != nil {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxOCSPResponseSize))
+	if err != nil {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+
+	response, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+
+	if !o.fresh(response) {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+
+	if nonce != nil && !bytes.Equal(ocspNonce(response.Extensions), nonce) {
+		return nil, errors.ErrOCSPCheckFailed
+	}
+
+	return response, nil
+}
+
+// fresh reports whether the response is produced within the maximum age and is not expired,
+// the thisUpdate time in the future is accepted within the clock skew
+func (o *OCSPChecker) fresh(response *ocsp.Response) bool {
+	now := o.currentTime()
+
+	if response.ThisUpdate.After(now.Add(o.clockSkew)) {
+		return false
+	}
+
+	if now.Sub(response.ThisUpdate) > o.maxAge {
+		return false
+	}
+
+	return response.NextUpdate.IsZero() || !now.After(response.NextUpdate)
+}
+
+func (o *OCSPChecker) load(key string) (ocspEntry, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.cache[key]
+	if !ok {
+		return ocspEntry{}, false
+	}
+
+	if o.currentTime().After(entry.nextUpdate) {
+		delete(o.cache, key)
+		return ocspEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (o *OCSPChecker) store(key string, entry ocspEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := o.currentTime()
+	for k, item := range o.cache {
+		if now.After(item.nextUpdate) {
+			delete(o.cache, k)
+		}
+	}
+
+	o.cache[key] = entry
+}
+
+type ocspRequest struct {
+	TBSRequest ocspTBSRequest
+}
+
+type ocspTBSRequest struct {
+	Version           int `asn1:"explicit,tag:0,default:0,optional"`
+	RequestList       []asn1.RawValue
+	RequestExtensions []pkix.Extension `asn1:"explicit,tag:2,optional"`
+}
+
+// createOCSPRequest creates the OCSP request of the certificate, the nonce extension is added when the nonce is given
+func createOCSPRequest(cert, issuer *x509.Certificate, nonce []byte) ([]byte, error) {
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil || nonce == nil {
+		return request, err
+	}
+
+	var req ocspRequest
+	if _, err = asn1.Unmarshal(request, &req); err != nil {
+		return nil, err
+	}
+
+	value, err := asn1.Marshal(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	req.TBSRequest.RequestExtensions = []pkix.Extension{{Id: oidOCSPNonce, Value: value}}
+	return asn1.Marshal(req)
+}
+
+// ocspNonce returns the nonce of the OCSP extensions
+func ocspNonce(extensions []pkix.Extension) []byte {
+	for _, extension := range extensions {
+		if !extension.Id.Equal(oidOCSPNonce) {
+			continue
+		}
+
+		var nonce []byte
+		if _, err := asn1.Unmarshal(extension.Value, &nonce); err != nil {
+			return nil
+		}
+		return nonce
+	}
+
+	return nil
+}
+
+func ocspCacheKey(cert, issuer *x509.Certificate) string {
+	hash := sha256.Sum256(issuer.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(hash[:]) + ":" + cert.SerialNumber.String()
+}
+
+func ocspStatus(value int) error {
+	switch value {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return errors.ErrCertificateRevoked
+	default:
+		return errors.ErrCertificateStatusUnknown
+	}
+}
@@ -0,0 +1,320 @@
+package mobileid
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+// newTestOCSPResponder creates a stand-in OCSP responder signing the responses with the issuer key,
+// the nonce of the request is echoed and the options modify the response template
+func newTestOCSPResponder(
+	t *testing.T,
+	issuer *testCA,
+	status int,
+	nextUpdate time.Duration,
+	calls *int32,
+	options ...func(template *ocsp.Response),
+) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		request, err := ocsp.ParseRequest(body)
+		assert.NoError(t, err)
+
+		template := ocsp.Response{
+			Status:       status,
+			SerialNumber: request.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(nextUpdate),
+			RevokedAt:    time.Now().Add(-time.Hour),
+		}
+
+		var req ocspRequest
+		_, err = asn1.Unmarshal(body, &req)
+		assert.NoError(t, err)
+		template.ExtraExtensions = req.TBSRequest.RequestExtensions
+
+		for _, option := range options {
+			option(&template)
+		}
+
+		response, err := ocsp.CreateResponse(issuer.cert, issuer.cert, template, issuer.key)
+		assert.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(response)
+	}))
+}
+
+func Test_OCSPChecker_Check(t *testing.T) {
+	ctx := context.Background()
+
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+	other := newTestCA(t, nil, "Unknown CA")
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	tests := []struct {
+		name       string
+		status     int
+		signer     *testCA
+		nextUpdate time.Duration
+		policy     OCSPPolicy
+		responder  func(server *httptest.Server) string
+		calls      int32
+		err        error
+	}{
+		{
+			name:       "Success: good",
+			status:     ocsp.Good,
+			signer:     intermediate,
+			nextUpdate: time.Hour,
+			policy:     OCSPFailClosed,
+			responder:  func(server *httptest.Server) string { return server.URL },
+			calls:      1,
+			err:        nil,
+		},
+		{
+			name:       "Error: stale response",
+			status:     ocsp.Good,
+			signer:     intermediate,
+			nextUpdate: -time.Second,
+			policy:     OCSPFailClosed,
+			responder:  func(server *httptest.Server) string { return server.URL },
+			calls:      2,
+			err:        errors.ErrOCSPCheckFailed,
+		},
+		{
+			name:       "Error: revoked",
+			status:     ocsp.Revoked,
+			signer:     intermediate,
+			nextUpdate: time.Hour,
+			policy:     OCSPFailOpen,
+			responder:  func(server *httptest.Server) string { return server.URL },
+			calls:      1,
+			err:        errors.ErrCertificateRevoked,
+		},
+		{
+			name:       "Error: unknown",
+			status:     ocsp.Unknown,
+			signer:     intermediate,
+			nextUpdate: time.Hour,
+			policy:     OCSPFailClosed,
+			responder:  func(server *httptest.Server) string { return server.URL },
+			calls:      1,
+			err:        errors.ErrCertificateStatusUnknown,
+		},
+		{
+			name:       "Error: response signed by another CA",
+			status:     ocsp.Good,
+			signer:     other,
+			nextUpdate: time.Hour,
+			policy:     OCSPFailClosed,
+			responder:  func(server *httptest.Server) string { return server.URL },
+			calls:      2,
+			err:        errors.ErrOCSPCheckFailed,
+		},
+		{
+			name:       "Error: responder unavailable (fail-closed)",
+			status:     ocsp.Good,
+			signer:     intermediate,
+			nextUpdate: time.Hour,
+			policy:     OCSPFailClosed,
+			responder:  func(*httptest.Server) string { return unavailable.URL },
+			calls:      0,
+			err:        errors.ErrOCSPCheckFailed,
+		},
+		{
+			name:       "Success: responder unavailable (fail-open)",
+			status:     ocsp.Good,
+			signer:     intermediate,
+			nextUpdate: time.Hour,
+			policy:     OCSPFailOpen,
+			responder:  func(*httptest.Server) string { return unavailable.URL },
+			calls:      0,
+			err:        nil,
+		},
+		{
+			name:       "Error: responder not found",
+			status:     ocsp.Good,
+			signer:     intermediate,
+			nextUpdate: time.Hour,
+			policy:     OCSPFailClosed,
+			responder:  func(*httptest.Server) string { return "" },
+			calls:      0,
+			err:        errors.ErrOCSPResponderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			server := newTestOCSPResponder(t, tt.signer, tt.status, tt.nextUpdate, &calls)
+			defer server.Close()
+
+			identity := issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+				if url := tt.responder(server); url != "" {
+					c.OCSPServer = []string{url}
+				}
+			})
+
+			checker := NewOCSPChecker().WithPolicy(tt.policy).WithTimeout(time.Second)
+
+			err := checker.Check(ctx, identity.certificate, intermediate.cert)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_ = checker.Check(ctx, identity.certificate, intermediate.cert)
+			assert.Equal(t, tt.calls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func Test_OCSPChecker_WithResponderURL(t *testing.T) {
+	ctx := context.Background()
+
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+
+	var calls int32
+	server := newTestOCSPResponder(t, intermediate, ocsp.Good, time.Hour, &calls)
+	defer server.Close()
+
+	identity := issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+		c.OCSPServer = []string{"http://aia.demo.sk.ee/eidq2024e"}
+	})
+
+	checker := NewOCSPChecker().WithResponderURL(server.URL)
+
+	err := checker.Check(ctx, identity.certificate, intermediate.cert)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_OCSPChecker_Freshness(t *testing.T) {
+	ctx := context.Background()
+
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+
+	tests := []struct {
+		name    string
+		checker func(c *OCSPChecker) *OCSPChecker
+		option  func(template *ocsp.Response)
+		err     error
+	}{
+		{
+			name:    "Success: Without next update",
+			checker: func(c *OCSPChecker) *OCSPChecker { return c },
+			option:  func(template *ocsp.Response) { template.NextUpdate = time.Time{} },
+			err:     nil,
+		},
+		{
+			name:    "Error: This update is older than max age",
+			checker: func(c *OCSPChecker) *OCSPChecker { return c.WithMaxAge(time.Hour) },
+			option: func(template *ocsp.Response) {
+				template.ThisUpdate = time.Now().Add(-2 * time.Hour)
+				template.NextUpdate = time.Time{}
+			},
+			err: errors.ErrOCSPCheckFailed,
+		},
+		{
+			name:    "Success: This update in the future within clock skew",
+			checker: func(c *OCSPChecker) *OCSPChecker { return c.WithClockSkew(5 * time.Minute) },
+			option:  func(template *ocsp.Response) { template.ThisUpdate = time.Now().Add(time.Minute) },
+			err:     nil,
+		},
+		{
+			name:    "Error: This update in the future beyond clock skew",
+			checker: func(c *OCSPChecker) *OCSPChecker { return c.WithClockSkew(5 * time.Minute) },
+			option:  func(template *ocsp.Response) { template.ThisUpdate = time.Now().Add(10 * time.Minute) },
+			err:     errors.ErrOCSPCheckFailed,
+		},
+		{
+			name:    "Success: Nonce is echoed",
+			checker: func(c *OCSPChecker) *OCSPChecker { return c.WithNonce(true) },
+			option:  func(template *ocsp.Response) {},
+			err:     nil,
+		},
+		{
+			name:    "Error: Nonce is missing",
+			checker: func(c *OCSPChecker) *OCSPChecker { return c.WithNonce(true) },
+			option:  func(template *ocsp.Response) { template.ExtraExtensions = nil },
+			err:     errors.ErrOCSPCheckFailed,
+		},
+		{
+			name:    "Error: Nonce does not match",
+			checker: func(c *OCSPChecker) *OCSPChecker { return c.WithNonce(true) },
+			option: func(template *ocsp.Response) {
+				value, _ := asn1.Marshal([]byte("replayed"))
+				template.ExtraExtensions[0].Value = value
+			},
+			err: errors.ErrOCSPCheckFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			server := newTestOCSPResponder(t, intermediate, ocsp.Good, time.Hour, &calls, tt.option)
+			defer server.Close()
+
+			identity := issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+				c.OCSPServer = []string{server.URL}
+			})
+
+			checker := tt.checker(NewOCSPChecker())
+
+			err := checker.Check(ctx, identity.certificate, intermediate.cert)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_OCSPChecker_Store(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	checker := NewOCSPChecker()
+	checker.currentTime = func() time.Time { return now }
+
+	checker.store("expired", ocspEntry{status: ocsp.Good, nextUpdate: now.Add(time.Minute)})
+	checker.store("valid", ocspEntry{status: ocsp.Good, nextUpdate: now.Add(time.Hour)})
+
+	now = now.Add(10 * time.Minute)
+	checker.store("new", ocspEntry{status: ocsp.Good, nextUpdate: now.Add(time.Hour)})
+
+	assert.Len(t, checker.cache, 2)
+	assert.NotContains(t, checker.cache, "expired")
+	assert.Contains(t, checker.cache, "valid")
+	assert.Contains(t, checker.cache, "new")
+}
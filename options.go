@@ -0,0 +1,72 @@
+package mobileid
+
+import (
+	"github.com/tab/mobileid/internal/config"
+)
+
+const (
+	TextFormatGSM7 = "GSM-7"
+	TextFormatUCS2 = "UCS-2"
+
+	LanguageEST = "EST"
+	LanguageENG = "ENG"
+	LanguageRUS = "RUS"
+	LanguageLIT = "LIT"
+)
+
+// SessionOption overrides the client configuration for a single session
+type SessionOption func(cfg *config.Config)
+
+// WithSessionText sets the text displayed on the phone of the person for the session
+func WithSessionText(text string) SessionOption {
+	return func(cfg *config.Config) {
+		cfg.Text = text
+	}
+}
+
+// WithSessionTextFormat sets the encoding of the displayed text for the session, GSM-7 or UCS-2
+func WithSessionTextFormat(format string) SessionOption {
+	return func(cfg *config.Config) {
+		cfg.TextFormat = format
+	}
+}
+
+// WithSessionLanguage sets the language of the phone dialog for the session, EST, ENG, RUS or LIT
+func WithSessionLanguage(language string) SessionOption {
+	return func(cfg *config.Config) {
+		cfg.Language = language
+	}
+}
+
+// WithSessionHashType sets the hash type of the authentication hash for the session, SHA256, SHA384 or SHA512
+func WithSessionHashType(hashType string) SessionOption {
+	return func(cfg *config.Config) {
+		cfg.HashType = hashType
+	}
+}
+
+// sessionConfig returns the client configuration with the session options applied and the text format resolved,
+// the overridden values are validated the same way as the client configuration
+func (c *client) sessionConfig(opts []SessionOption) (*config.Config, error) {
+	if len(opts) == 0 && !c.config.AutoTextFormat {
+		return c.config, nil
+	}
+
+	cfg := *c.config
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if len(opts) > 0 {
+		v := &validation{}
+		validateDisplay(v, &cfg)
+		if err := v.err(); err != nil {
+			return nil, err
+		}
+	}
+	cfg.TextFormat = textFormat(&cfg)
+
+	return &cfg, nil
+}
@@ -0,0 +1,264 @@
+package mobileid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/models"
+)
+
+func Test_CreateSession_Options(t *testing.T) {
+	type result struct {
+		text       string
+		textFormat string
+		language   string
+		hashType   string
+	}
+
+	tests := []struct {
+		name     string
+		opts     []SessionOption
+		expected result
+		err      error
+	}{
+		{
+			name: "Default values",
+			opts: nil,
+			expected: result{
+				text:       Text,
+				textFormat: TextFormatGSM7,
+				language:   LanguageENG,
+				hashType:   "SHA512",
+			},
+		},
+		{
+			name: "Overridden values",
+			opts: []SessionOption{
+				WithSessionText("Sisesta PIN1"),
+				WithSessionTextFormat(TextFormatUCS2),
+				WithSessionLanguage(LanguageEST),
+				WithSessionHashType("SHA256"),
+			},
+			expected: result{
+				text:       "Sisesta PIN1",
+				textFormat: TextFormatUCS2,
+				language:   LanguageEST,
+				hashType:   "SHA256",
+			},
+		},
+		{
+			name: "Partially overridden values",
+			opts: []SessionOption{
+				WithSessionLanguage(LanguageRUS),
+				nil,
+			},
+			expected: result{
+				text:       Text,
+				textFormat: TextFormatGSM7,
+				language:   LanguageRUS,
+				hashType:   "SHA512",
+			},
+		},
+		{
+			name: "Error: Unsupported language",
+			opts: []SessionOption{WithSessionLanguage("FIN")},
+			err:  ErrUnsupportedLanguage,
+		},
+		{
+			name: "Error: Unsupported text format",
+			opts: []SessionOption{WithSessionTextFormat("UTF-8")},
+			err:  ErrUnsupportedTextFormat,
+		},
+		{
+			name: "Error: Unsupported hash type",
+			opts: []SessionOption{WithSessionHashType("MD5")},
+			err:  ErrUnsupportedHashType,
+		},
+		{
+			name: "Error: Text outside GSM-7 alphabet",
+			opts: []SessionOption{WithSessionText("Введите PIN1")},
+			err:  ErrTextNotGSM7,
+		},
+		{
+			name: "Error: Text too long",
+			opts: []SessionOption{WithSessionText("Введите PIN1 для входа"), WithSessionTextFormat(TextFormatUCS2)},
+			err:  ErrTextTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body models.AuthenticationRequest
+			requests := 0
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				_ = json.NewDecoder(r.Body).Decode(&body)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL)
+
+			session, err := c.CreateSession(context.Background(), "+37269930366", "51307149560", tt.opts...)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, session)
+				assert.Equal(t, 0, requests)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.text, body.DisplayText)
+			assert.Equal(t, tt.expected.textFormat, body.DisplayTextFormat)
+			assert.Equal(t, tt.expected.language, body.Language)
+			assert.Equal(t, tt.expected.hashType, body.HashType)
+			assert.Equal(t, tt.expected.hashType, session.HashType)
+
+			assert.Equal(t, Text, c.(*client).config.Text)
+			assert.Equal(t, LanguageENG, c.(*client).config.Language)
+		})
+	}
+}
+
+func Test_Authenticate_Options(t *testing.T) {
+	var body models.AuthenticationRequest
+
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+			return
+		}
+
+		w.Write(identity.response(t, body.Hash))
+	}))
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(identity.trustStore(t))
+
+	person, err := c.Authenticate(context.Background(), "+37269930366", "51307149560", nil,
+		WithSessionLanguage(LanguageLIT),
+		WithSessionText("Įveskite PIN1"),
+		WithSessionTextFormat(TextFormatUCS2),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "51307149560", person.PersonalCode)
+	assert.Equal(t, LanguageLIT, body.Language)
+	assert.Equal(t, "Įveskite PIN1", body.DisplayText)
+	assert.Equal(t, TextFormatUCS2, body.DisplayTextFormat)
+}
+
+func Test_CreateSession_AutoTextFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		auto     bool
+		opts     []SessionOption
+		expected string
+		err      error
+	}{
+		{
+			name:     "GSM-7 text",
+			auto:     true,
+			opts:     []SessionOption{WithSessionText("Sisesta PIN1")},
+			expected: TextFormatGSM7,
+		},
+		{
+			name:     "Estonian text",
+			auto:     true,
+			opts:     []SessionOption{WithSessionText("Sisesta PIN1, õnne!")},
+			expected: TextFormatUCS2,
+		},
+		{
+			name:     "Russian text",
+			auto:     true,
+			opts:     []SessionOption{WithSessionText("Введите PIN1")},
+			expected: TextFormatUCS2,
+		},
+		{
+			name: "Russian text too long for UCS-2",
+			auto: true,
+			opts: []SessionOption{WithSessionText("Введите PIN1 для входа")},
+			err:  ErrTextTooLong,
+		},
+		{
+			name: "Disabled",
+			auto: false,
+			opts: []SessionOption{WithSessionText("Введите PIN1")},
+			err:  ErrTextNotGSM7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body models.AuthenticationRequest
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithAutoTextFormat(tt.auto)
+
+			_, err := c.CreateSession(context.Background(), "+37269930366", "51307149560", tt.opts...)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, body.DisplayTextFormat)
+		})
+	}
+}
+
+func Test_CreateSignatureSession_AutoTextFormat(t *testing.T) {
+	var body models.SignatureRequest
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+	}))
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithText("Allkirjasta leping õigesti").
+		WithAutoTextFormat(true)
+
+	_, err := c.CreateSignatureSession(context.Background(), "+37269930366", "51307149560", nil, make([]byte, 32), "SHA256")
+	assert.NoError(t, err)
+	assert.Equal(t, TextFormatUCS2, body.DisplayTextFormat)
+	assert.Equal(t, TextFormatGSM7, c.(*client).config.TextFormat)
+}
@@ -0,0 +1,127 @@
+// Package prometheus provides the Prometheus adapter of the mobileid.Metrics interface
+package prometheus
+
+import (
+	"strconv"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const Namespace = "mobileid"
+
+// Metrics is the Prometheus implementation of the mobileid.Metrics interface
+type Metrics struct {
+	sessionsCreated    *prom.CounterVec
+	sessionResults     *prom.CounterVec
+	providerErrors     *prom.CounterVec
+	createDuration     *prom.HistogramVec
+	pollDuration       *prom.HistogramVec
+	completionDuration *prom.HistogramVec
+	queueDepth         prom.Gauge
+	busyWorkers        prom.Gauge
+}
+
+// NewMetrics creates the collectors and registers them with the given registerer,
+// the default registerer is used when the registerer is nil
+func NewMetrics(registerer prom.Registerer) (*Metrics, error) {
+	if registerer == nil {
+		registerer = prom.DefaultRegisterer
+	}
+
+	m := &Metrics{
+		sessionsCreated: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: Namespace,
+			Name:      "sessions_created_total",
+			Help:      "Number of the Mobile-ID sessions created",
+		}, []string{"flow"}),
+		sessionResults: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: Namespace,
+			Name:      "session_results_total",
+			Help:      "Number of the completed Mobile-ID sessions by the result code",
+		}, []string{"flow", "result"}),
+		providerErrors: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: Namespace,
+			Name:      "provider_errors_total",
+			Help:      "Number of the Mobile-ID provider error responses by the HTTP status code",
+		}, []string{"flow", "status_code"}),
+		createDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "create_duration_seconds",
+			Help:      "Duration of the Mobile-ID session creation requests",
+			Buckets:   prom.DefBuckets,
+		}, []string{"flow"}),
+		pollDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "poll_duration_seconds",
+			Help:      "Duration of the Mobile-ID session status requests, including the long-poll wait",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+		}, []string{"flow"}),
+		completionDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "completion_duration_seconds",
+			Help:      "Duration from the Mobile-ID session creation until the session is completed",
+			Buckets:   []float64{1, 2.5, 5, 10, 15, 20, 30, 45, 60, 90, 120, 180, 300},
+		}, []string{"flow"}),
+		queueDepth: prom.NewGauge(prom.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "worker_queue_depth",
+			Help:      "Number of the jobs waiting in the worker queue",
+		}),
+		busyWorkers: prom.NewGauge(prom.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "worker_busy",
+			Help:      "Number of the worker goroutines polling a session",
+		}),
+	}
+
+	collectors := []prom.Collector{
+		m.sessionsCreated,
+		m.sessionResults,
+		m.providerErrors,
+		m.createDuration,
+		m.pollDuration,
+		m.completionDuration,
+		m.queueDepth,
+		m.busyWorkers,
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Metrics) SessionCreated(flow string) {
+	m.sessionsCreated.WithLabelValues(flow).Inc()
+}
+
+func (m *Metrics) SessionResult(flow, result string) {
+	m.sessionResults.WithLabelValues(flow, result).Inc()
+}
+
+func (m *Metrics) ProviderError(flow string, statusCode int) {
+	m.providerErrors.WithLabelValues(flow, strconv.Itoa(statusCode)).Inc()
+}
+
+func (m *Metrics) CreateLatency(flow string, duration time.Duration) {
+	m.createDuration.WithLabelValues(flow).Observe(duration.Seconds())
+}
+
+func (m *Metrics) PollLatency(flow string, duration time.Duration) {
+	m.pollDuration.WithLabelValues(flow).Observe(duration.Seconds())
+}
+
+func (m *Metrics) CompletionLatency(flow string, duration time.Duration) {
+	m.completionDuration.WithLabelValues(flow).Observe(duration.Seconds())
+}
+
+func (m *Metrics) QueueDepth(depth int) {
+	m.queueDepth.Set(float64(depth))
+}
+
+func (m *Metrics) BusyWorkers(busy int) {
+	m.busyWorkers.Set(float64(busy))
+}
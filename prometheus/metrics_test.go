@@ -0,0 +1,82 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid"
+)
+
+func Test_NewMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		before func(registry *prom.Registry)
+		err    bool
+	}{
+		{
+			name:   "Success",
+			before: func(registry *prom.Registry) {},
+			err:    false,
+		},
+		{
+			name: "Already registered",
+			before: func(registry *prom.Registry) {
+				_, _ = NewMetrics(registry)
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prom.NewRegistry()
+			tt.before(registry)
+
+			m, err := NewMetrics(registry)
+			if tt.err {
+				assert.Error(t, err)
+				assert.Nil(t, m)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, m)
+			}
+		})
+	}
+}
+
+func Test_Metrics(t *testing.T) {
+	registry := prom.NewRegistry()
+
+	m, err := NewMetrics(registry)
+	assert.NoError(t, err)
+
+	var _ mobileid.Metrics = m
+
+	m.SessionCreated(mobileid.FlowAuthentication)
+	m.SessionCreated(mobileid.FlowAuthentication)
+	m.SessionResult(mobileid.FlowAuthentication, mobileid.OK)
+	m.SessionResult(mobileid.FlowAuthentication, mobileid.USER_CANCELLED)
+	m.ProviderError(mobileid.FlowSignature, 400)
+	m.CreateLatency(mobileid.FlowAuthentication, 150*time.Millisecond)
+	m.PollLatency(mobileid.FlowAuthentication, 5*time.Second)
+	m.CompletionLatency(mobileid.FlowAuthentication, 12*time.Second)
+	m.QueueDepth(3)
+	m.BusyWorkers(2)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.sessionsCreated.WithLabelValues(mobileid.FlowAuthentication)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.sessionResults.WithLabelValues(mobileid.FlowAuthentication, mobileid.OK)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.sessionResults.WithLabelValues(mobileid.FlowAuthentication, mobileid.USER_CANCELLED)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.providerErrors.WithLabelValues(mobileid.FlowSignature, "400")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.queueDepth))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.busyWorkers))
+
+	assert.Equal(t, 3, testutil.CollectAndCount(registry,
+		"mobileid_create_duration_seconds",
+		"mobileid_poll_duration_seconds",
+		"mobileid_completion_duration_seconds",
+	))
+}
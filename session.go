@@ -1,6 +1,122 @@
 package mobileid
 
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/tab/mobileid/internal/utils"
+)
+
+// SessionLifetime is the period the created session is kept by the client, the upper bound of the session timeout
+const SessionLifetime = 5 * time.Minute
+
+// Session represents the authentication or signature session created with the Mobile-ID provider,
+// it keeps the challenge hash required to verify the signature, the URL of the endpoint the session is created with
+// and the signer certificate of the signature session passed by the caller, e.g. from FetchCertificate,
+// the certificate is encoded to JSON as base64 DER, the session created by the client is shared with its session store and must not be modified
 type Session struct {
-	Id   string `json:"sessionID"`
-	Code string `json:"code"`
+	Id                     string    `json:"sessionID"`
+	Code                   string    `json:"code"`
+	Hash                   string    `json:"hash"`
+	HashType               string    `json:"hashType"`
+	PhoneNumber            string    `json:"phoneNumber"`
+	NationalIdentityNumber string    `json:"nationalIdentityNumber"`
+	URL                    string    `json:"url,omitempty"`
+	CreatedAt              time.Time `json:"createdAt"`
+	ExpiresAt              time.Time `json:"expiresAt"`
+
+	Certificate *x509.Certificate `json:"-"`
+}
+
+// sessionJSON is the JSON representation of the session with the base64 DER encoded signer certificate
+type sessionJSON struct {
+	*sessionAlias
+	Cert string `json:"cert,omitempty"`
+}
+
+type sessionAlias Session
+
+// MarshalJSON encodes the session with the signer certificate, so the persisted signature session
+// can be passed to FetchSignatureSessionFor
+func (s Session) MarshalJSON() ([]byte, error) {
+	alias := sessionAlias(s)
+	data := sessionJSON{sessionAlias: &alias}
+
+	if s.Certificate != nil {
+		data.Cert = base64.StdEncoding.EncodeToString(s.Certificate.Raw)
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON decodes the session with the signer certificate
+func (s *Session) UnmarshalJSON(b []byte) error {
+	data := sessionJSON{sessionAlias: (*sessionAlias)(s)}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	s.Certificate = nil
+	if data.Cert != "" {
+		cert, err := utils.ParseCertificate(data.Cert)
+		if err != nil {
+			return err
+		}
+		s.Certificate = cert
+	}
+
+	return nil
+}
+
+// Expired reports whether the session lifetime has passed
+func (s *Session) Expired() bool {
+	return !s.ExpiresAt.IsZero() && time.Now().After(s.ExpiresAt)
+}
+
+// sessions keeps the created sessions until they are completed or expired
+type sessions struct {
+	mu    sync.Mutex
+	items map[string]*Session
+}
+
+func newSessions() *sessions {
+	return &sessions{
+		items: make(map[string]*Session),
+	}
+}
+
+func (s *sessions) store(session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, item := range s.items {
+		if item.Expired() {
+			delete(s.items, id)
+		}
+	}
+
+	s.items[session.Id] = session
+}
+
+func (s *sessions) load(id string) (*Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.items[id]
+	if ok && session.Expired() {
+		delete(s.items, id)
+		return nil, false
+	}
+
+	return session, ok
+}
+
+func (s *sessions) delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, id)
 }
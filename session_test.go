@@ -0,0 +1,118 @@
+package mobileid
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+func Test_Session_Expired(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  *Session
+		expected bool
+	}{
+		{
+			name:     "Not expired",
+			session:  &Session{ExpiresAt: time.Now().Add(time.Minute)},
+			expected: false,
+		},
+		{
+			name:     "Expired",
+			session:  &Session{ExpiresAt: time.Now().Add(-time.Minute)},
+			expected: true,
+		},
+		{
+			name:     "Without expiration",
+			session:  &Session{},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.session.Expired())
+		})
+	}
+}
+
+func Test_Sessions(t *testing.T) {
+	s := newSessions()
+
+	active := &Session{Id: "active", ExpiresAt: time.Now().Add(time.Minute)}
+	expired := &Session{Id: "expired", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	s.store(expired)
+	s.store(active)
+
+	session, ok := s.load("active")
+	assert.True(t, ok)
+	assert.Equal(t, active, session)
+
+	_, ok = s.load("expired")
+	assert.False(t, ok)
+	assert.Len(t, s.items, 1)
+
+	s.delete("active")
+	_, ok = s.load("active")
+	assert.False(t, ok)
+}
+
+func Test_Session_JSON(t *testing.T) {
+	identity := newTestSigningIdentity(t, "PNOEE-60001017869", "EID2016,TESTNUMBER")
+	createdAt := time.Date(2025, 2, 23, 17, 31, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		session Session
+	}{
+		{
+			name: "Signature session",
+			session: Session{
+				Id:                     "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+				Code:                   "1234",
+				Hash:                   "kXTM8ZUZdpsbHRH+sJBTr8fN1+tVkB7DNNdCnPpvJd3mw4aY2FWmFn1EBhvTBrSLmvFMXmxYYi3Ep6jFiWwyRA==",
+				HashType:               "SHA512",
+				PhoneNumber:            "+37268000769",
+				NationalIdentityNumber: "60001017869",
+				CreatedAt:              createdAt,
+				ExpiresAt:              createdAt.Add(SessionLifetime),
+				Certificate:            identity.certificate,
+			},
+		},
+		{
+			name: "Without certificate",
+			session: Session{
+				Id:        "eb03076a-9f97-423e-af2e-b14c0a481ff9",
+				Hash:      "kXTM8ZUZdpsbHRH+sJBTr8fN1+tVkB7DNNdCnPpvJd3mw4aY2FWmFn1EBhvTBrSLmvFMXmxYYi3Ep6jFiWwyRA==",
+				CreatedAt: createdAt,
+				ExpiresAt: createdAt.Add(SessionLifetime),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(&tt.session)
+			assert.NoError(t, err)
+
+			if tt.session.Certificate == nil {
+				assert.NotContains(t, string(data), `"cert"`)
+			}
+
+			var session Session
+			assert.NoError(t, json.Unmarshal(data, &session))
+			assert.Equal(t, tt.session, session)
+		})
+	}
+}
+
+func Test_Session_UnmarshalJSON_InvalidCertificate(t *testing.T) {
+	var session Session
+	err := json.Unmarshal([]byte(`{"sessionID": "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43", "cert": "invalid"}`), &session)
+	assert.Equal(t, errors.ErrFailedToDecodeCertificate, err)
+}
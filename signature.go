@@ -0,0 +1,247 @@
+package mobileid
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tab/mobileid/internal/errors"
+	"github.com/tab/mobileid/internal/models"
+	"github.com/tab/mobileid/internal/requests"
+	"github.com/tab/mobileid/internal/utils"
+)
+
+// Signature represents the signature created with the Mobile-ID provider
+type Signature struct {
+	Value       []byte
+	Algorithm   string
+	Certificate *x509.Certificate
+}
+
+// SignatureError represents a signing error from the Mobile-ID provider
+type SignatureError struct {
+	Code string
+}
+
+// Error returns the error message
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("signing failed: %s", e.Code)
+}
+
+// Is reports whether the target is the error of the result code
+func (e *SignatureError) Is(target error) bool {
+	err, ok := resultErrors[e.Code]
+	return ok && err == target
+}
+
+// CreateSignatureSession creates signature session with the Mobile-ID provider for the given digest,
+// the signer certificate, e.g. from FetchCertificate, is kept with the session to verify the signature,
+// as the provider usually does not return it with the signature
+func (c *client) CreateSignatureSession(
+	ctx context.Context,
+	phoneNumber, nationalIdentityNumber string,
+	certificate *x509.Certificate,
+	digest []byte,
+	hashType string,
+) (result *Session, err error) {
+	hashType = strings.ToUpper(hashType)
+
+	ctx, span := c.startSpan(ctx, "mobileid.CreateSignatureSession", attributeHashType.String(hashType))
+	defer func() { endSpan(span, err) }()
+
+	hash, err := utils.EncodeDigest(hashType, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := c.sessionConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	session, err := requests.CreateSignatureSession(ctx, c.http(), cfg, phoneNumber, nationalIdentityNumber, hash, hashType)
+	c.metrics.CreateLatency(FlowSignature, time.Since(start))
+	if err != nil {
+		c.recordProviderError(FlowSignature, err)
+		c.logError(ctx, "Mobile-ID signature session creation failed", err,
+			c.pii("phone_number", phoneNumber),
+			c.pii("national_identity_number", nationalIdentityNumber),
+		)
+		return nil, err
+	}
+
+	span.SetAttributes(attributeSessionId.String(session.Id))
+
+	createdAt := time.Now()
+	result = &Session{
+		Id:                     session.Id,
+		Code:                   session.Code,
+		Hash:                   session.Hash,
+		HashType:               hashType,
+		PhoneNumber:            phoneNumber,
+		NationalIdentityNumber: nationalIdentityNumber,
+		URL:                    session.URL,
+		CreatedAt:              createdAt,
+		ExpiresAt:              createdAt.Add(SessionLifetime),
+		Certificate:            certificate,
+	}
+	c.sessions.store(result)
+	c.metrics.SessionCreated(FlowSignature)
+
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "Mobile-ID signature session created",
+		slog.String("session_id", result.Id),
+		slog.String("url", result.URL),
+		slog.String("hash_type", hashType),
+		c.pii("phone_number", phoneNumber),
+		c.pii("national_identity_number", nationalIdentityNumber),
+	)
+
+	return result, nil
+}
+
+// FetchSignatureSession fetches the signature session created by the client from the Mobile-ID provider
+func (c *client) FetchSignatureSession(ctx context.Context, sessionId string) (*Signature, error) {
+	session, ok := c.sessions.load(sessionId)
+	if !ok {
+		return nil, errors.ErrMissingSessionHash
+	}
+
+	return c.FetchSignatureSessionFor(ctx, session)
+}
+
+// FetchSignatureSessionFor fetches the given signature session from the Mobile-ID provider
+// and verifies the signature against the hash and the signer certificate of the session,
+// the certificate returned by the provider is used when the session has none and must match it otherwise,
+// the signer certificate is verified against the trust store and the OCSP checker and must belong to the user of the session
+func (c *client) FetchSignatureSessionFor(ctx context.Context, session *Session) (signature *Signature, err error) {
+	if session == nil || session.Hash == "" {
+		return nil, errors.ErrMissingSessionHash
+	}
+	sessionId := session.Id
+
+	ctx, span := c.startSpan(ctx, "mobileid.FetchSignatureSession", attributeSessionId.String(sessionId))
+	defer func() { endSpan(span, err) }()
+
+	start := time.Now()
+	response, err := requests.FetchSignatureSession(ctx, c.http(), c.config, session.URL, sessionId)
+	c.metrics.PollLatency(FlowSignature, time.Since(start))
+	if err != nil {
+		c.recordProviderError(FlowSignature, err)
+		c.logError(ctx, "Mobile-ID signature session poll failed", err, slog.String("session_id", sessionId))
+		return nil, err
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "Mobile-ID signature session polled",
+		slog.String("session_id", sessionId),
+		slog.String("state", response.State),
+	)
+	span.SetAttributes(attributeState.String(response.State), attributeResult.String(response.Result))
+
+	switch response.State {
+	case Running:
+		return nil, errors.ErrSignatureIsRunning
+	case Complete:
+		c.sessions.delete(sessionId)
+		c.recordResult(FlowSignature, session, response.Result)
+
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "Mobile-ID signature session completed",
+			slog.String("session_id", sessionId),
+			slog.String("result", response.Result),
+		)
+
+		switch response.Result {
+		case OK:
+			signature, err = c.verifySignature(ctx, session, response)
+			if err != nil {
+				c.logError(ctx, "Mobile-ID signature verification failed", err, slog.String("session_id", sessionId))
+				return nil, err
+			}
+
+			return signature, nil
+		case NOT_MID_CLIENT,
+			USER_CANCELLED,
+			SIGNATURE_HASH_MISMATCH,
+			PHONE_ABSENT,
+			DELIVERY_ERROR,
+			SIM_ERROR,
+			TIMEOUT:
+			return nil, &SignatureError{Code: response.Result}
+		}
+	default:
+		return nil, errors.ErrUnsupportedState
+	}
+
+	return nil, errors.ErrUnsupportedResult
+}
+
+// verifySignature verifies the signature against the hash of the session and the signer certificate,
+// the certificate is verified against the trust store and its revocation status and must belong to the user of the session
+func (c *client) verifySignature(
+	ctx context.Context,
+	session *Session,
+	response *models.SignatureResponse,
+) (*Signature, error) {
+	value, err := base64.StdEncoding.DecodeString(response.Signature.Value)
+	if err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+
+	cert, err := signerCertificate(session, response.Cert)
+	if err != nil {
+		return nil, err
+	}
+
+	err = utils.VerifySignature(cert, session.Hash, response.Signature.Value, response.Signature.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.verifyCertificate(ctx, cert, x509.KeyUsageContentCommitment); err != nil {
+		return nil, err
+	}
+
+	person, err := utils.ExtractFromCertificate(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.NationalIdentityNumber == "" {
+		return nil, errors.ErrMissingIdentityNumber
+	}
+
+	if person.PersonalCode != session.NationalIdentityNumber {
+		return nil, errors.ErrIdentityNumberMismatch
+	}
+
+	return &Signature{
+		Value:       value,
+		Algorithm:   response.Signature.Algorithm,
+		Certificate: cert,
+	}, nil
+}
+
+// signerCertificate returns the certificate the signature of the session is verified against
+func signerCertificate(session *Session, value string) (*x509.Certificate, error) {
+	if value == "" {
+		if session.Certificate == nil {
+			return nil, errors.ErrMissingSignerCertificate
+		}
+		return session.Certificate, nil
+	}
+
+	cert, err := utils.ParseCertificate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Certificate != nil && !session.Certificate.Equal(cert) {
+		return nil, errors.ErrSignerCertificateMismatch
+	}
+
+	return cert, nil
+}
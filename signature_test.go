@@ -0,0 +1,407 @@
+package mobileid
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/errors"
+	"github.com/tab/mobileid/internal/models"
+	"github.com/tab/mobileid/internal/utils"
+)
+
+func Test_CreateSignatureSession(t *testing.T) {
+	ctx := context.Background()
+
+	digest := sha256.Sum256([]byte("document"))
+
+	tests := []struct {
+		name     string
+		before   func(w http.ResponseWriter, r *http.Request)
+		digest   []byte
+		hashType string
+		err      error
+	}{
+		{
+			name: "Success",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				var body models.SignatureRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, "SHA256", body.HashType)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"sessionID": "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43"}`))
+			},
+			digest:   digest[:],
+			hashType: "sha256",
+			err:      nil,
+		},
+		{
+			name: "Error: Digest does not match hash type",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("request must not be sent")
+			},
+			digest:   digest[:],
+			hashType: "SHA512",
+			err:      errors.ErrInvalidDigest,
+		},
+		{
+			name: "Error: Unsupported hash type",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("request must not be sent")
+			},
+			digest:   digest[:],
+			hashType: "MD5",
+			err:      errors.ErrUnsupportedHashType,
+		},
+		{
+			name: "Error: Internal Server Error",
+			before: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			digest:   digest[:],
+			hashType: "SHA256",
+			err:      errors.ErrMobileIdProviderError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(tt.before))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL)
+
+			session, err := c.CreateSignatureSession(ctx, "+37268000769", "60001017869", nil, tt.digest, tt.hashType)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, session)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43", session.Id)
+				assert.Equal(t, "SHA256", session.HashType)
+				assert.Len(t, session.Code, 4)
+
+				code, err := utils.GenerateVerificationCode(session.Hash)
+				assert.NoError(t, err)
+				assert.Equal(t, code, session.Code)
+			}
+		})
+	}
+}
+
+func Test_FetchSignatureSession(t *testing.T) {
+	ctx := context.Background()
+
+	identity := newTestSigningIdentity(t, "PNOEE-60001017869", "EID2016,TESTNUMBER")
+	another := issueTestIdentity(t, identity.ca, "PNOEE-50001029996", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+		c.KeyUsage = x509.KeyUsageContentCommitment
+	})
+	digest := sha512.Sum512([]byte("document"))
+	other := sha512.Sum512([]byte("another document"))
+
+	tests := []struct {
+		name        string
+		before      func(w http.ResponseWriter, r *http.Request, hash string)
+		sessionId   string
+		certificate *x509.Certificate
+		err         error
+	}{
+		{
+			name: "Success",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(identity.response(t, hash))
+			},
+			sessionId: "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			err:       nil,
+		},
+		{
+			name: "Success: Certificate of the session",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(withoutCertificate(t, identity.response(t, hash)))
+			},
+			sessionId:   "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			certificate: identity.certificate,
+			err:         nil,
+		},
+		{
+			name: "Error: Missing signer certificate",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(withoutCertificate(t, identity.response(t, hash)))
+			},
+			sessionId: "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			err:       errors.ErrMissingSignerCertificate,
+		},
+		{
+			name: "Error: Signer certificate mismatch",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(identity.response(t, hash))
+			},
+			sessionId:   "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			certificate: another.certificate,
+			err:         errors.ErrSignerCertificateMismatch,
+		},
+		{
+			name: "Error: Identity number mismatch",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(another.response(t, hash))
+			},
+			sessionId: "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			err:       errors.ErrIdentityNumberMismatch,
+		},
+		{
+			name: "Error: Invalid signature of the session certificate",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(withoutCertificate(t, identity.response(t, hash)))
+			},
+			sessionId:   "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			certificate: another.certificate,
+			err:         errors.ErrInvalidSignature,
+		},
+		{
+			name: "Error: Invalid signature",
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
+				hash, _ := utils.EncodeDigest(utils.HashTypeSHA512, other[:])
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(identity.response(t, hash))
+			},
+			sessionId: "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			err:       errors.ErrInvalidSignature,
+		},
+		{
+			name: "Error: Missing session hash",
+			before: func(w http.ResponseWriter, r *http.Request, hash string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(identity.response(t, hash))
+			},
+			sessionId: "unknown",
+			err:       errors.ErrMissingSessionHash,
+		},
+		{
+			name: "Error: Signature is running",
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"state": "RUNNING"}`))
+			},
+			sessionId: "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			err:       errors.ErrSignatureIsRunning,
+		},
+		{
+			name: "Error: USER_CANCELLED",
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"state": "COMPLETE", "result": "USER_CANCELLED"}`))
+			},
+			sessionId: "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			err:       &SignatureError{Code: "USER_CANCELLED"},
+		},
+		{
+			name: "Error: result UNKNOWN",
+			before: func(w http.ResponseWriter, r *http.Request, _ string) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"state": "COMPLETE", "result": "UNKNOWN"}`))
+			},
+			sessionId: "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+			err:       errors.ErrUnsupportedResult,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hash string
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					var body models.SignatureRequest
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					hash = body.Hash
+
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"sessionID": "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43"}`))
+					return
+				}
+
+				assert.Equal(t, "/signature/session/d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43", r.URL.Path)
+				tt.before(w, r, hash)
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(identity.trustStore(t))
+
+			session, err := c.CreateSignatureSession(ctx, "+37268000769", "60001017869", tt.certificate, digest[:], utils.HashTypeSHA512)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.certificate, session.Certificate)
+
+			signature, err := c.FetchSignatureSession(ctx, tt.sessionId)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, signature)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, signature.Value)
+				assert.Equal(t, "SHA512WithECEncryption", signature.Algorithm)
+				assert.True(t, identity.certificate.Equal(signature.Certificate))
+			}
+		})
+	}
+}
+
+func Test_FetchSignatureSessionFor(t *testing.T) {
+	ctx := context.Background()
+
+	identity := newTestSigningIdentity(t, "PNOEE-60001017869", "EID2016,TESTNUMBER")
+	digest := sha512.Sum512([]byte("document"))
+	hash, err := utils.EncodeDigest(utils.HashTypeSHA512, digest[:])
+	assert.NoError(t, err)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/signature/session/d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(withoutCertificate(t, identity.response(t, hash)))
+	}))
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(identity.trustStore(t))
+
+	persisted, err := json.Marshal(&Session{
+		Id:                     "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+		Hash:                   hash,
+		HashType:               utils.HashTypeSHA512,
+		NationalIdentityNumber: "60001017869",
+		Certificate:            identity.certificate,
+	})
+	assert.NoError(t, err)
+
+	session := &Session{}
+	assert.NoError(t, json.Unmarshal(persisted, session))
+
+	signature, err := c.FetchSignatureSessionFor(ctx, session)
+	assert.NoError(t, err)
+	assert.Equal(t, identity.certificate, signature.Certificate)
+
+	_, err = c.FetchSignatureSessionFor(ctx, &Session{Id: session.Id})
+	assert.Equal(t, errors.ErrMissingSessionHash, err)
+}
+
+func Test_FetchSignatureSessionFor_TrustStore(t *testing.T) {
+	ctx := context.Background()
+
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+	unknown := newTestCA(t, nil, "Unknown CA")
+
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, root, intermediate))
+	assert.NoError(t, err)
+
+	hash, err := utils.GenerateHash(utils.HashTypeSHA512)
+	assert.NoError(t, err)
+
+	signing := func(c *x509.Certificate) {
+		c.KeyUsage = x509.KeyUsageContentCommitment
+	}
+
+	tests := []struct {
+		name     string
+		identity *testIdentity
+		err      error
+	}{
+		{
+			name:     "Success",
+			identity: issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", signing),
+			err:      nil,
+		},
+		{
+			name:     "Error: Certificate not trusted",
+			identity: issueTestIdentity(t, unknown, "PNOEE-60001017869", "EID2016,TESTNUMBER", signing),
+			err:      errors.ErrCertificateNotTrusted,
+		},
+		{
+			name:     "Error: Invalid key usage",
+			identity: issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}),
+			err:      errors.ErrInvalidCertificateKeyUsage,
+		},
+		{
+			name:     "Error: Identity number mismatch",
+			identity: issueTestIdentity(t, intermediate, "PNOEE-50001029996", "EID2016,TESTNUMBER", signing),
+			err:      errors.ErrIdentityNumberMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(tt.identity.response(t, hash))
+			}))
+			defer testServer.Close()
+
+			c := NewClient().
+				WithRelyingPartyName("DEMO").
+				WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+				WithURL(testServer.URL).
+				WithTrustStore(store)
+
+			signature, err := c.FetchSignatureSessionFor(ctx, &Session{
+				Id:                     "d2ad8a8c-2bd6-4e8c-a6ea-fd7f2a8b5c43",
+				Hash:                   hash,
+				HashType:               utils.HashTypeSHA512,
+				NationalIdentityNumber: "60001017869",
+			})
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, signature)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, tt.identity.certificate.Equal(signature.Certificate))
+			}
+		})
+	}
+}
+
+// withoutCertificate removes the signer certificate from the session response
+func withoutCertificate(t *testing.T, response []byte) []byte {
+	t.Helper()
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(response, &body))
+	delete(body, "cert")
+
+	result, err := json.Marshal(body)
+	assert.NoError(t, err)
+	return result
+}
+
+func Test_SignatureError(t *testing.T) {
+	err := &SignatureError{Code: "USER_CANCELLED"}
+	assert.Equal(t, "signing failed: USER_CANCELLED", err.Error())
+}
@@ -0,0 +1,47 @@
+package mobileid
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+// TracerName is the instrumentation name of the spans created by the client and the worker
+const TracerName = "github.com/tab/mobileid"
+
+const (
+	attributeSessionId = attribute.Key("mobileid.session_id")
+	attributeState     = attribute.Key("mobileid.state")
+	attributeResult    = attribute.Key("mobileid.result")
+	attributeHashType  = attribute.Key("mobileid.hash_type")
+	attributePolls     = attribute.Key("mobileid.polls")
+)
+
+// tracer returns the tracer of the provider, the global provider is used when the provider is not set
+func tracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return provider.Tracer(TracerName)
+}
+
+// startSpan starts the client span of the Mobile-ID API call
+func (c *client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer(c.tracerProvider).Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error of the span and ends it, the running session is not recorded as an error
+func endSpan(span trace.Span, err error) {
+	if err != nil && err != errors.ErrAuthenticationIsRunning && err != errors.ErrSignatureIsRunning {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
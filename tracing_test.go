@@ -0,0 +1,180 @@
+package mobileid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/tab/mobileid/internal/models"
+)
+
+func newTestTracing(t *testing.T) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	return provider, recorder
+}
+
+func newTestAuthenticationServer(t *testing.T, running int) (*httptest.Server, *TrustStore) {
+	identity := newTestIdentity(t, "PNOEE-51307149560", "MARY ÄNN,O'CONNEŽ-ŠUSLIK TESTNUMBER")
+
+	var hash string
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			var body models.AuthenticationRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			hash = body.Hash
+
+			w.Write([]byte(`{"sessionID": "eb03076a-9f97-423e-af2e-b14c0a481ff9"}`))
+			return
+		}
+
+		polls++
+		if polls <= running {
+			w.Write([]byte(`{"state": "RUNNING"}`))
+			return
+		}
+
+		w.Write(identity.response(t, hash))
+	}))
+
+	return server, identity.trustStore(t)
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	result := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		result[attr.Key] = attr.Value
+	}
+	return result
+}
+
+func Test_Tracing_Authenticate(t *testing.T) {
+	provider, recorder := newTestTracing(t)
+
+	testServer, store := newTestAuthenticationServer(t, 1)
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(store).
+		WithTracerProvider(provider)
+
+	_, err := c.Authenticate(context.Background(), "+37269930366", "51307149560", nil)
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 4)
+
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name())
+	}
+	assert.Equal(t, []string{"mobileid.CreateSession", "mobileid.FetchSession", "mobileid.FetchSession", "mobileid.Authenticate"}, names)
+
+	root := spans[3]
+	for _, span := range spans[:3] {
+		assert.Equal(t, root.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	}
+
+	create := spanAttributes(spans[0])
+	assert.Equal(t, "eb03076a-9f97-423e-af2e-b14c0a481ff9", create["mobileid.session_id"].AsString())
+	assert.Equal(t, int64(http.StatusOK), create["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(1), create["mobileid.attempts"].AsInt64())
+
+	running := spanAttributes(spans[1])
+	assert.Equal(t, Running, running["mobileid.state"].AsString())
+
+	completed := spanAttributes(spans[2])
+	assert.Equal(t, Complete, completed["mobileid.state"].AsString())
+	assert.Equal(t, OK, completed["mobileid.result"].AsString())
+
+	assert.Equal(t, int64(2), spanAttributes(root)["mobileid.polls"].AsInt64())
+}
+
+func Test_Tracing_Error(t *testing.T) {
+	provider, recorder := newTestTracing(t)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTracerProvider(provider)
+
+	_, err := c.CreateSession(context.Background(), "+37269930366", "51307149560")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, int64(http.StatusBadRequest), spanAttributes(spans[0])["http.response.status_code"].AsInt64())
+}
+
+func Test_Tracing_Worker(t *testing.T) {
+	provider, recorder := newTestTracing(t)
+
+	testServer, store := newTestAuthenticationServer(t, 2)
+	defer testServer.Close()
+
+	c := NewClient().
+		WithRelyingPartyName("DEMO").
+		WithRelyingPartyUUID("00000000-0000-0000-0000-000000000000").
+		WithURL(testServer.URL).
+		WithTrustStore(store).
+		WithTracerProvider(provider)
+
+	w := NewWorker(c).WithConcurrency(1).WithTracerProvider(provider)
+	w.Start(context.Background())
+	defer w.Stop()
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "login")
+
+	session, err := c.CreateSession(ctx, "+37269930366", "51307149560")
+	assert.NoError(t, err)
+
+	result := <-w.Process(ctx, session.Id)
+	assert.NoError(t, result.Err)
+	request.End()
+
+	var job sdktrace.ReadOnlySpan
+	var polls []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "mobileid.Worker.Process":
+			job = span
+		case "mobileid.FetchSession":
+			polls = append(polls, span)
+		}
+	}
+
+	assert.NotNil(t, job)
+	assert.Equal(t, request.SpanContext().SpanID(), job.Parent().SpanID())
+	assert.Equal(t, int64(3), spanAttributes(job)["mobileid.polls"].AsInt64())
+
+	assert.Len(t, polls, 3)
+	for _, span := range polls {
+		assert.Equal(t, job.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, request.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+}
@@ -0,0 +1,137 @@
+package mobileid
+
+import (
+	"bytes"
+	"crypto/x509"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tab/mobileid/internal/certificates"
+	"github.com/tab/mobileid/internal/errors"
+)
+
+const (
+	// TrustStoreDemo is the trust store environment for the SK demo CA certificates
+	TrustStoreDemo = "demo"
+
+	// TrustStoreProduction is the trust store environment for the SK production CA certificates
+	TrustStoreProduction = "production"
+
+	// TestCertificatePrefix is the common name prefix of the SK test CA certificates
+	TestCertificatePrefix = "TEST of"
+)
+
+type TrustStore struct {
+	environment   string
+	certificates  []*x509.Certificate
+	roots         *x509.CertPool
+	intermediates *x509.CertPool
+}
+
+// NewTrustStore creates a new trust store with the SK root and intermediate CA certificates of the environment,
+// only the environment subdirectory of the certificates directory is loaded, e.g. certsDir/demo for the demo environment,
+// demo trust store accepts only the test CA certificates, production trust store rejects them
+func NewTrustStore(environment, certsDir string) (*TrustStore, error) {
+	if environment != TrustStoreDemo && environment != TrustStoreProduction {
+		return nil, errors.ErrUnsupportedTrustStoreEnvironment
+	}
+
+	certs, err := certificates.LoadFromDir(filepath.Join(certsDir, environment))
+	if err != nil {
+		return nil, err
+	}
+
+	store := &TrustStore{
+		environment:   environment,
+		certificates:  certs,
+		roots:         x509.NewCertPool(),
+		intermediates: x509.NewCertPool(),
+	}
+
+	roots := 0
+	for _, cert := range certs {
+		if !cert.IsCA {
+			return nil, errors.ErrInvalidTrustStoreCertificate
+		}
+
+		if strings.HasPrefix(cert.Subject.CommonName, TestCertificatePrefix) != (environment == TrustStoreDemo) {
+			return nil, errors.ErrInvalidTrustStoreCertificate
+		}
+
+		if isSelfSigned(cert) {
+			store.roots.AddCert(cert)
+			roots++
+		} else {
+			store.intermediates.AddCert(cert)
+		}
+	}
+
+	if roots == 0 {
+		return nil, errors.ErrEmptyTrustStore
+	}
+
+	return store, nil
+}
+
+// Environment returns the trust store environment
+func (s *TrustStore) Environment() string {
+	return s.environment
+}
+
+// Verify verifies the user authentication certificate validity period, key usage and chain against the trust store
+func (s *TrustStore) Verify(cert *x509.Certificate) error {
+	return s.verify(cert, x509.KeyUsageDigitalSignature)
+}
+
+// VerifySigning verifies the user signing certificate validity period, non-repudiation key usage
+// and chain against the trust store
+func (s *TrustStore) VerifySigning(cert *x509.Certificate) error {
+	return s.verify(cert, x509.KeyUsageContentCommitment)
+}
+
+func (s *TrustStore) verify(cert *x509.Certificate, keyUsage x509.KeyUsage) error {
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return errors.ErrCertificateExpired
+	}
+
+	if cert.KeyUsage&keyUsage == 0 {
+		return errors.ErrInvalidCertificateKeyUsage
+	}
+
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         s.roots,
+		Intermediates: s.intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return errors.ErrCertificateNotTrusted
+	}
+
+	return nil
+}
+
+// Issuer returns the trust store CA certificate which issued the certificate
+func (s *TrustStore) Issuer(cert *x509.Certificate) (*x509.Certificate, error) {
+	for _, ca := range s.certificates {
+		if !bytes.Equal(cert.RawIssuer, ca.RawSubject) {
+			continue
+		}
+
+		if cert.CheckSignatureFrom(ca) == nil {
+			return ca, nil
+		}
+	}
+
+	return nil, errors.ErrCertificateNotTrusted
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false
+	}
+
+	return cert.CheckSignatureFrom(cert) == nil
+}
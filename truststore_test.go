@@ -0,0 +1,191 @@
+package mobileid
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tab/mobileid/internal/errors"
+)
+
+func Test_NewTrustStore(t *testing.T) {
+	root := newTestCA(t, nil, "SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "SK ID Solutions EID-Q 2024E")
+	testRoot := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	identity := newTestIdentity(t, "PNOEE-60001017869", "EID2016,TESTNUMBER")
+
+	tests := []struct {
+		name        string
+		environment string
+		dir         string
+		err         error
+	}{
+		{
+			name:        "Success: demo",
+			environment: TrustStoreDemo,
+			dir:         writeTestCAs(t, TrustStoreDemo, testRoot),
+			err:         nil,
+		},
+		{
+			name:        "Success: production",
+			environment: TrustStoreProduction,
+			dir:         writeTestCAs(t, TrustStoreProduction, root, intermediate),
+			err:         nil,
+		},
+		{
+			name:        "Error: Unsupported environment",
+			environment: "staging",
+			dir:         writeTestCAs(t, TrustStoreProduction, root),
+			err:         errors.ErrUnsupportedTrustStoreEnvironment,
+		},
+		{
+			name:        "Error: Test CA in production trust store",
+			environment: TrustStoreProduction,
+			dir:         writeTestCAs(t, TrustStoreProduction, testRoot),
+			err:         errors.ErrInvalidTrustStoreCertificate,
+		},
+		{
+			name:        "Error: Production CA in demo trust store",
+			environment: TrustStoreDemo,
+			dir:         writeTestCAs(t, TrustStoreDemo, testRoot, root),
+			err:         errors.ErrInvalidTrustStoreCertificate,
+		},
+		{
+			name:        "Error: CA set of another environment",
+			environment: TrustStoreProduction,
+			dir:         writeTestCAs(t, TrustStoreDemo, testRoot),
+			err:         errors.ErrFailedToReadCertificateFile,
+		},
+		{
+			name:        "Error: Not a CA certificate",
+			environment: TrustStoreDemo,
+			dir:         writeTestCAs(t, TrustStoreDemo, &testCA{cert: identity.certificate}),
+			err:         errors.ErrInvalidTrustStoreCertificate,
+		},
+		{
+			name:        "Error: Without root CA",
+			environment: TrustStoreProduction,
+			dir:         writeTestCAs(t, TrustStoreProduction, intermediate),
+			err:         errors.ErrEmptyTrustStore,
+		},
+		{
+			name:        "Error: Failed to read certificate file",
+			environment: TrustStoreDemo,
+			dir:         "internal/certificates/testdata/missing",
+			err:         errors.ErrFailedToReadCertificateFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewTrustStore(tt.environment, tt.dir)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, store)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.environment, store.Environment())
+			}
+		})
+	}
+}
+
+func Test_TrustStore_Verify(t *testing.T) {
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+	unknown := newTestCA(t, nil, "Unknown CA")
+
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, root, intermediate))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		err  error
+	}{
+		{
+			name: "Success",
+			cert: issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}).certificate,
+			err:  nil,
+		},
+		{
+			name: "Error: Expired certificate",
+			cert: issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+				c.NotBefore = time.Now().Add(-2 * time.Hour)
+				c.NotAfter = time.Now().Add(-time.Hour)
+			}).certificate,
+			err: errors.ErrCertificateExpired,
+		},
+		{
+			name: "Error: Invalid key usage",
+			cert: issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+				c.KeyUsage = x509.KeyUsageKeyEncipherment
+			}).certificate,
+			err: errors.ErrInvalidCertificateKeyUsage,
+		},
+		{
+			name: "Error: Issued by unknown CA",
+			cert: issueTestIdentity(t, unknown, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}).certificate,
+			err:  errors.ErrCertificateNotTrusted,
+		},
+		{
+			name: "Error: Self-signed certificate",
+			cert: issueTestIdentity(t, nil, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}).certificate,
+			err:  errors.ErrCertificateNotTrusted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Verify(tt.cert)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_TrustStore_VerifySigning(t *testing.T) {
+	root := newTestCA(t, nil, "TEST of SK ID Solutions ROOT G1E")
+	intermediate := newTestCA(t, root, "TEST of SK ID Solutions EID-Q 2024E")
+
+	store, err := NewTrustStore(TrustStoreDemo, writeTestCAs(t, TrustStoreDemo, root, intermediate))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		err  error
+	}{
+		{
+			name: "Success",
+			cert: issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(c *x509.Certificate) {
+				c.KeyUsage = x509.KeyUsageContentCommitment
+			}).certificate,
+			err: nil,
+		},
+		{
+			name: "Error: Authentication certificate",
+			cert: issueTestIdentity(t, intermediate, "PNOEE-60001017869", "EID2016,TESTNUMBER", func(*x509.Certificate) {}).certificate,
+			err:  errors.ErrInvalidCertificateKeyUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.VerifySigning(tt.cert)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package mobileid
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tab/mobileid/internal/config"
+	"github.com/tab/mobileid/internal/errors"
+	"github.com/tab/mobileid/internal/requests"
+	"github.com/tab/mobileid/internal/utils"
+)
+
+// validation collects the invalid fields of the config
+type validation struct {
+	errors []*errors.FieldError
+}
+
+// check adds the error of the field when the value is not valid
+func (v *validation) check(valid bool, field string, err error) {
+	if !valid {
+		v.errors = append(v.errors, &errors.FieldError{Field: field, Err: err})
+	}
+}
+
+// err returns the ValidationError with all invalid fields or nil when the config is valid
+func (v *validation) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &errors.ValidationError{Errors: v.errors}
+}
+
+// validateConfig validates every field of the client config
+func validateConfig(cfg *config.Config) error {
+	v := &validation{}
+
+	v.check(cfg.RelyingPartyName != "", "RelyingPartyName", errors.ErrMissingRelyingPartyName)
+	if cfg.RelyingPartyUUID == "" {
+		v.check(false, "RelyingPartyUUID", errors.ErrMissingRelyingPartyUUID)
+	} else {
+		v.check(isUUID(cfg.RelyingPartyUUID), "RelyingPartyUUID", errors.ErrInvalidRelyingPartyUUID)
+	}
+
+	validateDisplay(v, cfg)
+
+	v.check(isURL(cfg.URL), "URL", errors.ErrInvalidURL)
+	for i, failoverURL := range cfg.FailoverURLs {
+		v.check(isURL(failoverURL), fmt.Sprintf("FailoverURLs[%d]", i), errors.ErrInvalidURL)
+	}
+
+	v.check(cfg.Timeout >= requests.MinMobileIdTimeout*time.Millisecond &&
+		cfg.Timeout <= requests.MaxMobileIdTimeout*time.Millisecond, "Timeout", errors.ErrInvalidTimeout)
+	v.check(cfg.RequestTimeout > 0, "RequestTimeout", errors.ErrInvalidRequestTimeout)
+	v.check(cfg.SessionTimeout > 0 && cfg.SessionTimeout <= SessionLifetime, "SessionTimeout", errors.ErrInvalidSessionTimeout)
+
+	v.check(cfg.MaxIdleConns >= 0, "MaxIdleConns", errors.ErrInvalidConnectionPool)
+	v.check(cfg.MaxIdleConnsPerHost >= 0, "MaxIdleConnsPerHost", errors.ErrInvalidConnectionPool)
+	v.check(cfg.MaxConnsPerHost >= 0, "MaxConnsPerHost", errors.ErrInvalidConnectionPool)
+	v.check(cfg.IdleConnTimeout >= 0, "IdleConnTimeout", errors.ErrInvalidConnectionPool)
+	v.check(cfg.KeepAlive >= 0, "KeepAlive", errors.ErrInvalidConnectionPool)
+
+	policy := cfg.RetryPolicy
+	v.check(policy.MaxAttempts >= 0 && policy.InitialBackoff >= 0 && policy.MaxBackoff >= 0 &&
+		policy.Multiplier >= 0 && policy.Jitter >= 0 && policy.Jitter <= 1, "RetryPolicy", errors.ErrInvalidRetryPolicy)
+
+	return v.err()
+}
+
+// validateDisplay validates the hash type, the text format and the language of the config
+func validateDisplay(v *validation, cfg *config.Config) {
+	switch strings.ToUpper(cfg.HashType) {
+	case utils.HashTypeSHA256, utils.HashTypeSHA384, utils.HashTypeSHA512:
+	default:
+		v.check(false, "HashType", errors.ErrUnsupportedHashType)
+	}
+
+	switch textFormat(cfg) {
+	case TextFormatGSM7:
+		length, ok := utils.GSM7Length(cfg.Text)
+		v.check(ok, "Text", errors.ErrTextNotGSM7)
+		v.check(length <= utils.MaxGSM7TextLength, "Text", errors.ErrTextTooLong)
+	case TextFormatUCS2:
+		length, ok := utils.UCS2Length(cfg.Text)
+		v.check(ok, "Text", errors.ErrTextNotUCS2)
+		v.check(length <= utils.MaxUCS2TextLength, "Text", errors.ErrTextTooLong)
+	default:
+		v.check(false, "TextFormat", errors.ErrUnsupportedTextFormat)
+	}
+
+	switch cfg.Language {
+	case LanguageEST, LanguageENG, LanguageRUS, LanguageLIT:
+	default:
+		v.check(false, "Language", errors.ErrUnsupportedLanguage)
+	}
+}
+
+// textFormat returns the text format the text is sent with,
+// GSM-7 is switched to UCS-2 when AutoTextFormat is enabled and the text does not fit the GSM-7 alphabet
+func textFormat(cfg *config.Config) string {
+	if cfg.AutoTextFormat && cfg.TextFormat == TextFormatGSM7 {
+		if _, ok := utils.GSM7Length(cfg.Text); !ok {
+			return TextFormatUCS2
+		}
+	}
+	return cfg.TextFormat
+}
+
+// isUUID reports whether the value is a UUID in the canonical 8-4-4-4-12 hex form
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+
+	for i, r := range value {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isURL reports whether the value is an absolute http or https URL
+func isURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
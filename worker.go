@@ -2,12 +2,20 @@ package mobileid
 
 import (
 	"context"
+	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tab/mobileid/internal/errors"
 )
 
 const (
-	DefaultConcurrency = 10
-	DefaultQueueSize   = 100
+	DefaultConcurrency    = 10
+	DefaultQueueSize      = 100
+	DefaultSessionTimeout = 2 * time.Minute
 )
 
 type Result struct {
@@ -18,29 +26,57 @@ type Result struct {
 type Job struct {
 	SessionId string
 	ResultCh  chan Result
+
+	ctx      context.Context
+	session  *Session
+	deadline time.Time
+	span     trace.Span
+	polls    int
 }
 
 type Worker interface {
 	Start(ctx context.Context)
 	Stop()
 	Process(ctx context.Context, sessionId string) <-chan Result
+	ProcessSession(ctx context.Context, session *Session) <-chan Result
 
 	WithConcurrency(concurrency int) Worker
 	WithQueueSize(size int) Worker
+	WithSessionTimeout(timeout time.Duration) Worker
+	WithLogger(logger *slog.Logger) Worker
+	WithTracerProvider(provider trace.TracerProvider) Worker
+	WithMetrics(metrics Metrics) Worker
+	WithAuditSink(sink AuditSink) Worker
 }
 
 type worker struct {
-	client      Client
-	queue       chan Job
-	concurrency int
-	wg          sync.WaitGroup
+	client         Client
+	queue          chan Job
+	concurrency    int
+	sessionTimeout time.Duration
+	logger         *slog.Logger
+	tracerProvider trace.TracerProvider
+	metrics        Metrics
+	auditSink      AuditSink
+	busy           atomic.Int64
+	running        atomic.Int64
+	wg             sync.WaitGroup
+	mu             sync.RWMutex
+	stopped        bool
+	done           chan struct{}
+	shutdown       sync.Once
 }
 
 func NewWorker(client Client) Worker {
 	return &worker{
-		client:      client,
-		queue:       make(chan Job, DefaultQueueSize),
-		concurrency: DefaultConcurrency,
+		client:         client,
+		queue:          make(chan Job, DefaultQueueSize),
+		concurrency:    DefaultConcurrency,
+		sessionTimeout: sessionTimeout(client),
+		logger:         discardLogger,
+		metrics:        noopMetrics{},
+		auditSink:      noopAuditSink{},
+		done:           make(chan struct{}),
 	}
 }
 
@@ -62,26 +98,143 @@ func (w *worker) WithQueueSize(size int) Worker {
 	return w
 }
 
+// WithSessionTimeout sets the overall deadline of polling a single session, it overrides the session timeout
+// of the client for the worker jobs, the session timeout of the client is used by default,
+// the timeout is limited to SessionLifetime the created session is kept by the client for
+func (w *worker) WithSessionTimeout(timeout time.Duration) Worker {
+	if timeout <= 0 {
+		timeout = sessionTimeout(w.client)
+	}
+	timeout = min(timeout, SessionLifetime)
+
+	w.sessionTimeout = timeout
+	return w
+}
+
+// WithLogger sets the logger of the worker lifecycle events, nothing is logged by default
+func (w *worker) WithLogger(logger *slog.Logger) Worker {
+	if logger == nil {
+		logger = discardLogger
+	}
+
+	w.logger = logger
+	return w
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider of the job spans, the global provider is used by default
+func (w *worker) WithTracerProvider(provider trace.TracerProvider) Worker {
+	w.tracerProvider = provider
+	return w
+}
+
+// WithMetrics sets the metrics of the queue depth and the busy goroutines, nothing is recorded by default
+func (w *worker) WithMetrics(metrics Metrics) Worker {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	w.metrics = metrics
+	return w
+}
+
+// WithAuditSink sets the audit sink of the job results, nothing is recorded by default
+func (w *worker) WithAuditSink(sink AuditSink) Worker {
+	if sink == nil {
+		sink = noopAuditSink{}
+	}
+
+	w.auditSink = sink
+	return w
+}
+
 func (w *worker) Start(ctx context.Context) {
+	w.logger.LogAttrs(ctx, slog.LevelInfo, "Mobile-ID worker started",
+		slog.Int("concurrency", w.concurrency),
+		slog.Int("queue_size", cap(w.queue)),
+	)
+
 	for i := 0; i < w.concurrency; i++ {
 		w.wg.Add(1)
+		w.running.Add(1)
 		go w.perform(ctx)
 	}
 }
 
+// Stop stops the worker and waits for the running polls to return, the session being polled
+// and the jobs left in the queue are completed with ErrWorkerStopped
 func (w *worker) Stop() {
+	w.close()
+
+	w.mu.Lock()
+	w.stopped = true
 	close(w.queue)
+	w.mu.Unlock()
+
 	w.wg.Wait()
+
+	w.logger.Info("Mobile-ID worker stopped")
 }
 
+// Process queues the session created by the client of the worker to be polled,
+// see ProcessSession for the sessions created by another instance, the job span is started
+// from the given context and passed through the queue, so the polling spans are linked to the originating request,
+// ErrWorkerStopped is returned when the worker is stopped
 func (w *worker) Process(ctx context.Context, sessionId string) <-chan Result {
+	return w.enqueue(ctx, sessionId, nil)
+}
+
+// ProcessSession queues the given session to be polled with FetchSessionFor,
+// e.g. the session persisted by another instance which created it
+func (w *worker) ProcessSession(ctx context.Context, session *Session) <-chan Result {
+	if session == nil {
+		resultCh := make(chan Result, 1)
+		resultCh <- Result{Err: errors.ErrMissingSessionHash}
+		close(resultCh)
+		return resultCh
+	}
+
+	return w.enqueue(ctx, session.Id, session)
+}
+
+// enqueue queues the job, the send to a full queue is released by Stop, so Stop is not blocked by the waiting callers
+func (w *worker) enqueue(ctx context.Context, sessionId string, session *Session) <-chan Result {
 	resultCh := make(chan Result, 1)
 
+	ctx, span := tracer(w.tracerProvider).Start(ctx, "mobileid.Worker.Process",
+		trace.WithAttributes(attributeSessionId.String(sessionId)),
+	)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.stopped || w.stopping() {
+		endSpan(span, errors.ErrWorkerStopped)
+		resultCh <- Result{Err: errors.ErrWorkerStopped}
+		close(resultCh)
+		return resultCh
+	}
+
+	job := Job{
+		SessionId: sessionId,
+		ResultCh:  resultCh,
+		ctx:       ctx,
+		session:   session,
+		deadline:  time.Now().Add(w.sessionTimeout),
+		span:      span,
+	}
+
 	select {
 	case <-ctx.Done():
+		endSpan(span, ctx.Err())
 		resultCh <- Result{Err: ctx.Err()}
 		close(resultCh)
-	case w.queue <- Job{SessionId: sessionId, ResultCh: resultCh}:
+	case <-w.done:
+		endSpan(span, errors.ErrWorkerStopped)
+		resultCh <- Result{Err: errors.ErrWorkerStopped}
+		close(resultCh)
+	case w.queue <- job:
+		w.metrics.QueueDepth(len(w.queue))
+		w.logger.LogAttrs(ctx, slog.LevelDebug, "Mobile-ID worker job queued", slog.String("session_id", sessionId))
 	}
 
 	return resultCh
@@ -89,6 +242,7 @@ func (w *worker) Process(ctx context.Context, sessionId string) <-chan Result {
 
 func (w *worker) perform(ctx context.Context) {
 	defer w.wg.Done()
+	defer w.exit(ctx)
 
 	for {
 		select {
@@ -96,13 +250,175 @@ func (w *worker) perform(ctx context.Context) {
 			if !ok {
 				return
 			}
+			w.metrics.QueueDepth(len(w.queue))
+
+			if err := ctx.Err(); err != nil {
+				w.complete(j, Result{Err: err})
+				continue
+			}
 
-			person, err := w.client.FetchSession(ctx, j.SessionId)
-			j.ResultCh <- Result{Person: person, Err: err}
+			if w.stopping() {
+				w.complete(j, Result{Err: errors.ErrWorkerStopped})
+				continue
+			}
 
-			close(j.ResultCh)
+			w.metrics.BusyWorkers(int(w.busy.Add(1)))
+			w.handle(ctx, j)
+			w.metrics.BusyWorkers(int(w.busy.Add(-1)))
 		case <-ctx.Done():
 			return
 		}
 	}
 }
+
+// exit stops the worker when the last goroutine returns on the cancelled context,
+// the jobs left in the queue are completed with the context error, so the callers are not blocked on their results
+func (w *worker) exit(ctx context.Context) {
+	if w.running.Add(-1) > 0 || ctx.Err() == nil {
+		return
+	}
+
+	w.close()
+
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+
+	for {
+		select {
+		case j, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			w.complete(j, Result{Err: ctx.Err()})
+		default:
+			w.metrics.QueueDepth(0)
+			return
+		}
+	}
+}
+
+// handle polls the session and re-queues it while the authentication is running,
+// the result is sent when the session is completed or the job deadline is exceeded
+func (w *worker) handle(ctx context.Context, j Job) {
+	jobCtx, cancel := context.WithDeadline(j.ctx, j.deadline)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	for {
+		if err := jobCtx.Err(); err != nil {
+			w.complete(j, Result{Err: err})
+			return
+		}
+
+		j.polls++
+		person, err := w.fetch(jobCtx, j)
+		if err != errors.ErrAuthenticationIsRunning {
+			if ctxErr := jobCtx.Err(); err != nil && ctxErr != nil {
+				err = ctxErr
+			}
+
+			w.complete(j, Result{Person: person, Err: err})
+			return
+		}
+
+		queued, stopped := w.requeue(j)
+		if queued {
+			return
+		}
+
+		if stopped {
+			err = errors.ErrWorkerStopped
+			if ctxErr := jobCtx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+
+			w.complete(j, Result{Err: err})
+			return
+		}
+	}
+}
+
+// fetch polls the session of the job, the session passed to ProcessSession is polled with FetchSessionFor
+func (w *worker) fetch(ctx context.Context, j Job) (*Person, error) {
+	if j.session != nil {
+		return w.client.FetchSessionFor(ctx, j.session)
+	}
+
+	return w.client.FetchSession(ctx, j.SessionId)
+}
+
+// requeue puts the job back to the queue, when the queue is full the job is polled again by the same goroutine
+func (w *worker) requeue(j Job) (queued bool, stopped bool) {
+	if w.stopping() {
+		return false, true
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.stopped {
+		return false, true
+	}
+
+	select {
+	case w.queue <- j:
+		w.metrics.QueueDepth(len(w.queue))
+		return true, false
+	default:
+		return false, false
+	}
+}
+
+// close releases the callers waiting on the queue and the goroutines polling the sessions
+func (w *worker) close() {
+	w.shutdown.Do(func() { close(w.done) })
+}
+
+func (w *worker) stopping() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *worker) complete(j Job, result Result) {
+	record := AuditRecord{Event: AuditJobCompleted, SessionId: j.SessionId}
+
+	if result.Err != nil {
+		record.Event = AuditJobFailed
+		record.Error = result.Err.Error()
+
+		w.logger.LogAttrs(j.ctx, slog.LevelDebug, "Mobile-ID worker job failed",
+			slog.String("session_id", j.SessionId),
+			slog.String("error", result.Err.Error()),
+		)
+	} else {
+		if result.Person != nil {
+			record.NationalIdentityNumber = result.Person.PersonalCode
+		}
+
+		w.logger.LogAttrs(j.ctx, slog.LevelDebug, "Mobile-ID worker job completed", slog.String("session_id", j.SessionId))
+	}
+	audit(j.ctx, w.auditSink, w.logger, record)
+
+	j.span.SetAttributes(attributePolls.Int(j.polls))
+	endSpan(j.span, result.Err)
+
+	j.ResultCh <- result
+	close(j.ResultCh)
+}
+
+// sessionTimeout returns the session timeout configured on the client,
+// the default session timeout for other Client implementations
+func sessionTimeout(c Client) time.Duration {
+	if impl, ok := c.(*client); ok && impl.config.SessionTimeout > 0 {
+		return impl.config.SessionTimeout
+	}
+
+	return DefaultSessionTimeout
+}
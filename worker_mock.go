@@ -11,8 +11,11 @@ package mobileid
 
 import (
 	context "context"
+	slog "log/slog"
 	reflect "reflect"
+	time "time"
 
+	trace "go.opentelemetry.io/otel/trace"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -54,6 +57,20 @@ func (mr *MockWorkerMockRecorder) Process(ctx, sessionId any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockWorker)(nil).Process), ctx, sessionId)
 }
 
+// ProcessSession mocks base method.
+func (m *MockWorker) ProcessSession(ctx context.Context, session *Session) <-chan Result {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessSession", ctx, session)
+	ret0, _ := ret[0].(<-chan Result)
+	return ret0
+}
+
+// ProcessSession indicates an expected call of ProcessSession.
+func (mr *MockWorkerMockRecorder) ProcessSession(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessSession", reflect.TypeOf((*MockWorker)(nil).ProcessSession), ctx, session)
+}
+
 // Start mocks base method.
 func (m *MockWorker) Start(ctx context.Context) {
 	m.ctrl.T.Helper()
@@ -78,6 +95,20 @@ func (mr *MockWorkerMockRecorder) Stop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockWorker)(nil).Stop))
 }
 
+// WithAuditSink mocks base method.
+func (m *MockWorker) WithAuditSink(sink AuditSink) Worker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithAuditSink", sink)
+	ret0, _ := ret[0].(Worker)
+	return ret0
+}
+
+// WithAuditSink indicates an expected call of WithAuditSink.
+func (mr *MockWorkerMockRecorder) WithAuditSink(sink any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithAuditSink", reflect.TypeOf((*MockWorker)(nil).WithAuditSink), sink)
+}
+
 // WithConcurrency mocks base method.
 func (m *MockWorker) WithConcurrency(concurrency int) Worker {
 	m.ctrl.T.Helper()
@@ -92,6 +123,34 @@ func (mr *MockWorkerMockRecorder) WithConcurrency(concurrency any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithConcurrency", reflect.TypeOf((*MockWorker)(nil).WithConcurrency), concurrency)
 }
 
+// WithLogger mocks base method.
+func (m *MockWorker) WithLogger(logger *slog.Logger) Worker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithLogger", logger)
+	ret0, _ := ret[0].(Worker)
+	return ret0
+}
+
+// WithLogger indicates an expected call of WithLogger.
+func (mr *MockWorkerMockRecorder) WithLogger(logger any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLogger", reflect.TypeOf((*MockWorker)(nil).WithLogger), logger)
+}
+
+// WithMetrics mocks base method.
+func (m *MockWorker) WithMetrics(metrics Metrics) Worker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMetrics", metrics)
+	ret0, _ := ret[0].(Worker)
+	return ret0
+}
+
+// WithMetrics indicates an expected call of WithMetrics.
+func (mr *MockWorkerMockRecorder) WithMetrics(metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMetrics", reflect.TypeOf((*MockWorker)(nil).WithMetrics), metrics)
+}
+
 // WithQueueSize mocks base method.
 func (m *MockWorker) WithQueueSize(size int) Worker {
 	m.ctrl.T.Helper()
@@ -105,3 +164,31 @@ func (mr *MockWorkerMockRecorder) WithQueueSize(size any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithQueueSize", reflect.TypeOf((*MockWorker)(nil).WithQueueSize), size)
 }
+
+// WithSessionTimeout mocks base method.
+func (m *MockWorker) WithSessionTimeout(timeout time.Duration) Worker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithSessionTimeout", timeout)
+	ret0, _ := ret[0].(Worker)
+	return ret0
+}
+
+// WithSessionTimeout indicates an expected call of WithSessionTimeout.
+func (mr *MockWorkerMockRecorder) WithSessionTimeout(timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithSessionTimeout", reflect.TypeOf((*MockWorker)(nil).WithSessionTimeout), timeout)
+}
+
+// WithTracerProvider mocks base method.
+func (m *MockWorker) WithTracerProvider(provider trace.TracerProvider) Worker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTracerProvider", provider)
+	ret0, _ := ret[0].(Worker)
+	return ret0
+}
+
+// WithTracerProvider indicates an expected call of WithTracerProvider.
+func (mr *MockWorkerMockRecorder) WithTracerProvider(provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTracerProvider", reflect.TypeOf((*MockWorker)(nil).WithTracerProvider), provider)
+}
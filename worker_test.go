@@ -2,10 +2,14 @@ package mobileid
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+
+	"github.com/tab/mobileid/internal/errors"
 )
 
 func Test_NewWorker(t *testing.T) {
@@ -287,7 +291,7 @@ func Test_Worker_Process(t *testing.T) {
 			sessionId: "c2731f5e-9d63-4db7-b83c-db528d2f7021",
 			before: func() {
 				mockClient.EXPECT().
-					FetchSession(ctx, "c2731f5e-9d63-4db7-b83c-db528d2f7021").
+					FetchSession(gomock.Any(), "c2731f5e-9d63-4db7-b83c-db528d2f7021").
 					Return(&Person{
 						IdentityNumber: "PNOEE-30303039914",
 						PersonalCode:   "30303039914",
@@ -308,12 +312,39 @@ func Test_Worker_Process(t *testing.T) {
 			sessionId: "c2731f5e-9d63-4db7-b83c-db528d2f7021",
 			before: func() {
 				mockClient.EXPECT().
-					FetchSession(ctx, "c2731f5e-9d63-4db7-b83c-db528d2f7021").
+					FetchSession(gomock.Any(), "c2731f5e-9d63-4db7-b83c-db528d2f7021").
 					Return(nil, &Error{Code: "USER_REFUSED"})
 			},
 			expect: nil,
 			err:    &Error{Code: "USER_REFUSED"},
 		},
+		{
+			name:      "Success: Authentication is running",
+			sessionId: "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6",
+			before: func() {
+				gomock.InOrder(
+					mockClient.EXPECT().
+						FetchSession(gomock.Any(), "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6").
+						Return(nil, errors.ErrAuthenticationIsRunning).
+						Times(2),
+					mockClient.EXPECT().
+						FetchSession(gomock.Any(), "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6").
+						Return(&Person{
+							IdentityNumber: "PNOEE-30303039914",
+							PersonalCode:   "30303039914",
+							FirstName:      "TESTNUMBER",
+							LastName:       "OK",
+						}, nil),
+				)
+			},
+			expect: &Person{
+				IdentityNumber: "PNOEE-30303039914",
+				PersonalCode:   "30303039914",
+				FirstName:      "TESTNUMBER",
+				LastName:       "OK",
+			},
+			err: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -333,3 +364,299 @@ func Test_Worker_Process(t *testing.T) {
 		})
 	}
 }
+
+func Test_Worker_ProcessSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockClient := NewMockClient(ctrl)
+	w := NewWorker(mockClient).WithConcurrency(1)
+	w.Start(ctx)
+	defer w.Stop()
+
+	session := &Session{
+		Id:                     "5e5ab1e1-d2d4-4b8a-b7c4-5a6a1bd4c6b6",
+		Hash:                   "kXTM8ZUZdpsbHRH+sJBTr8fN1+tVkB7DNNdCnPpvJd3mw4aY2FWmFn1EBhvTBrSLmvFMXmxYYi3Ep6jFiWwyRA==",
+		NationalIdentityNumber: "30303039914",
+	}
+
+	tests := []struct {
+		name    string
+		session *Session
+		before  func()
+		expect  *Person
+		err     error
+	}{
+		{
+			name:    "Success",
+			session: session,
+			before: func() {
+				gomock.InOrder(
+					mockClient.EXPECT().
+						FetchSessionFor(gomock.Any(), session).
+						Return(nil, errors.ErrAuthenticationIsRunning),
+					mockClient.EXPECT().
+						FetchSessionFor(gomock.Any(), session).
+						Return(&Person{
+							IdentityNumber: "PNOEE-30303039914",
+							PersonalCode:   "30303039914",
+							FirstName:      "TESTNUMBER",
+							LastName:       "OK",
+						}, nil),
+				)
+			},
+			expect: &Person{
+				IdentityNumber: "PNOEE-30303039914",
+				PersonalCode:   "30303039914",
+				FirstName:      "TESTNUMBER",
+				LastName:       "OK",
+			},
+			err: nil,
+		},
+		{
+			name:    "Error: Nil session",
+			session: nil,
+			before:  func() {},
+			expect:  nil,
+			err:     errors.ErrMissingSessionHash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+
+			result := <-w.ProcessSession(ctx, tt.session)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, result.Err)
+			} else {
+				assert.NoError(t, result.Err)
+				assert.Equal(t, tt.expect, result.Person)
+			}
+		})
+	}
+}
+
+func Test_Worker_Process_SessionTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockClient := NewMockClient(ctrl)
+	w := NewWorker(mockClient).WithConcurrency(1).WithSessionTimeout(50 * time.Millisecond)
+	w.Start(ctx)
+	defer w.Stop()
+
+	mockClient.EXPECT().
+		FetchSession(gomock.Any(), "c2731f5e-9d63-4db7-b83c-db528d2f7021").
+		DoAndReturn(func(context.Context, string) (*Person, error) {
+			time.Sleep(5 * time.Millisecond)
+			return nil, errors.ErrAuthenticationIsRunning
+		}).
+		MinTimes(1)
+
+	result := <-w.Process(ctx, "c2731f5e-9d63-4db7-b83c-db528d2f7021")
+	assert.Equal(t, context.DeadlineExceeded, result.Err)
+	assert.Nil(t, result.Person)
+}
+
+func Test_Worker_Process_ContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := NewMockClient(ctrl)
+	w := NewWorker(mockClient).WithConcurrency(1)
+	w.Start(context.Background())
+	defer w.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockClient.EXPECT().
+		FetchSession(gomock.Any(), "c2731f5e-9d63-4db7-b83c-db528d2f7021").
+		DoAndReturn(func(context.Context, string) (*Person, error) {
+			cancel()
+			return nil, errors.ErrAuthenticationIsRunning
+		})
+
+	result := <-w.Process(ctx, "c2731f5e-9d63-4db7-b83c-db528d2f7021")
+	assert.Equal(t, context.Canceled, result.Err)
+}
+
+func Test_Worker_Start_ContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := NewMockClient(ctrl)
+	w := NewWorker(mockClient).WithConcurrency(1).WithQueueSize(5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Start(ctx)
+
+	polling := make(chan struct{})
+	mockClient.EXPECT().
+		FetchSession(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string) (*Person, error) {
+			close(polling)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	results := []<-chan Result{w.Process(context.Background(), "c2731f5e-9d63-4db7-b83c-db528d2f7021")}
+	<-polling
+
+	for i := 0; i < 3; i++ {
+		results = append(results, w.Process(context.Background(), fmt.Sprintf("session-%d", i)))
+	}
+
+	cancel()
+
+	for _, resultCh := range results {
+		select {
+		case result := <-resultCh:
+			assert.Equal(t, context.Canceled, result.Err)
+		case <-time.After(time.Second):
+			t.Fatal("result is not sent")
+		}
+	}
+
+	result := <-w.Process(context.Background(), "c2731f5e-9d63-4db7-b83c-db528d2f7021")
+	assert.Equal(t, errors.ErrWorkerStopped, result.Err)
+
+	w.Stop()
+}
+
+func Test_Worker_Process_Stopped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := NewMockClient(ctrl)
+	w := NewWorker(mockClient).WithConcurrency(1)
+	w.Start(context.Background())
+	w.Stop()
+
+	result := <-w.Process(context.Background(), "c2731f5e-9d63-4db7-b83c-db528d2f7021")
+	assert.Equal(t, errors.ErrWorkerStopped, result.Err)
+}
+
+func Test_Worker_Stop_QueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := NewMockClient(ctrl)
+	w := NewWorker(mockClient).WithConcurrency(1).WithQueueSize(1)
+	w.Start(context.Background())
+
+	polling := make(chan struct{}, 1)
+	mockClient.EXPECT().
+		FetchSession(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string) (*Person, error) {
+			select {
+			case polling <- struct{}{}:
+			default:
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil, errors.ErrAuthenticationIsRunning
+		}).
+		AnyTimes()
+
+	results := make(chan (<-chan Result), 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			results <- w.Process(context.Background(), fmt.Sprintf("session-%d", i))
+		}()
+	}
+
+	<-polling
+	assert.Eventually(t, func() bool {
+		return len(w.(*worker).queue) == 1
+	}, time.Second, time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("worker is not stopped")
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case resultCh := <-results:
+			result := <-resultCh
+			assert.Equal(t, errors.ErrWorkerStopped, result.Err)
+			assert.Nil(t, result.Person)
+		case <-time.After(time.Second):
+			t.Fatal("result is not sent")
+		}
+	}
+}
+
+func Test_Worker_WithSessionTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name     string
+		client   Client
+		param    time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "Success",
+			client:   NewClient(),
+			param:    30 * time.Second,
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "Zero value",
+			client:   NewClient(),
+			param:    0,
+			expected: DefaultSessionTimeout,
+		},
+		{
+			name:     "Zero value: Session timeout of the client",
+			client:   NewClient().WithSessionTimeout(5 * time.Minute),
+			param:    0,
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "Override: Session timeout of the client",
+			client:   NewClient().WithSessionTimeout(5 * time.Minute),
+			param:    time.Minute,
+			expected: time.Minute,
+		},
+		{
+			name:     "Limited to session lifetime",
+			client:   NewClient(),
+			param:    10 * time.Minute,
+			expected: SessionLifetime,
+		},
+		{
+			name:     "Zero value: Mock client",
+			client:   NewMockClient(ctrl),
+			param:    0,
+			expected: DefaultSessionTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorker(tt.client).WithSessionTimeout(tt.param)
+
+			workerImpl := w.(*worker)
+			assert.Equal(t, tt.expected, workerImpl.sessionTimeout)
+		})
+	}
+}
+
+func Test_NewWorker_SessionTimeout(t *testing.T) {
+	w := NewWorker(NewClient().WithSessionTimeout(5 * time.Minute))
+
+	workerImpl := w.(*worker)
+	assert.Equal(t, 5*time.Minute, workerImpl.sessionTimeout)
+}